@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 
 	"github.com/GettEngineering/effe/drawer"
+	"github.com/GettEngineering/effe/exporter"
 	"github.com/GettEngineering/effe/generator"
 	"github.com/GettEngineering/effe/loaders"
 	"github.com/GettEngineering/effe/strategies"
@@ -25,6 +27,11 @@ func main() {
 	showVerstionPtr := flag.Bool("v", false, "show current version of effe")
 	drawPtr := flag.Bool("d", false, "draw diagrams for business flows")
 	drawOutPtr := flag.String("out", "graphs", "draw output directory")
+	jsonPtr := flag.Bool("json", false, "write a JSON description of business flows to effe_flows.json")
+	drawTypesPtr := flag.Bool("types", false, "annotate steps on diagrams with consumed and produced types")
+	drawDepsPtr := flag.Bool("deps", false, "annotate steps on diagrams with types of dependencies")
+	drawInlinePtr := flag.Int("inline", 0, "draw nested flows on diagrams inline up to the depth")
+	drawFormatPtr := flag.String("format", "plantuml", "format of diagrams: plantuml, mermaid or dot")
 	flag.Parse()
 	if showVerstionPtr != nil && *showVerstionPtr {
 		showVersion()
@@ -37,11 +44,28 @@ func main() {
 		os.Exit(2)
 	}
 
+	drawOpts := make([]drawer.Option, 0)
+	if *drawTypesPtr {
+		drawOpts = append(drawOpts, drawer.WithTypes())
+	}
+	if *drawDepsPtr {
+		drawOpts = append(drawOpts, drawer.WithDeps())
+	}
+	if *drawInlinePtr > 0 {
+		drawOpts = append(drawOpts, drawer.WithNestedFlows(*drawInlinePtr))
+	}
+	flowDrawer, err := newDrawer(*drawFormatPtr, drawOpts...)
+	if err != nil {
+		log.Println(err)
+		os.Exit(2)
+	}
+
 	settings := generator.DefaultSettigs()
 	gen := generator.NewGenerator(
 		generator.WithSetttings(settings),
 		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
-		generator.WithDrawer(drawer.NewDrawer()),
+		generator.WithDrawer(flowDrawer),
+		generator.WithExporter(exporter.NewExporter()),
 		generator.WithStrategy(
 			strategies.NewChain(strategies.WithServiceObjectName(settings.LocalInterfaceVarname())),
 		),
@@ -52,7 +76,9 @@ func main() {
 		genResults []types.GenerateResult
 	)
 
-	if drawPtr != nil && *drawPtr {
+	if jsonPtr != nil && *jsonPtr {
+		genResults, errs = gen.GenerateJSON(context.Background(), d, os.Environ(), []string{"."})
+	} else if drawPtr != nil && *drawPtr {
 		if drawOutPtr == nil {
 			log.Println("directory for output is not set")
 			os.Exit(2)
@@ -80,6 +106,19 @@ func main() {
 	}
 }
 
+func newDrawer(format string, opts ...drawer.Option) (drawer.Drawer, error) {
+	switch format {
+	case "plantuml":
+		return drawer.NewDrawer(opts...), nil
+	case "mermaid":
+		return drawer.NewMermaidDrawer(opts...), nil
+	case "dot":
+		return drawer.NewDotDrawer(opts...), nil
+	default:
+		return nil, fmt.Errorf("unsupported format of diagrams %s", format)
+	}
+}
+
 func showVersion() {
 	log.Println(version)
 }
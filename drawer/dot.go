@@ -0,0 +1,66 @@
+package drawer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotSyntax draws graphs in Graphviz DOT language. Steps are drawn as nodes,
+// transitions between steps and failures as edges, wrap and timeout blocks as clusters.
+type dotSyntax struct{}
+
+func (dotSyntax) ext() string {
+	return "dot"
+}
+
+func (dotSyntax) wrap(flowName string, graph string) string {
+	return buildStmts([]string{
+		fmt.Sprintf("digraph %s {", dotText(flowName)),
+		fmt.Sprintf("label=%s;", dotText(flowName)),
+		"labelloc=t;",
+		"node [fontname=Helvetica];",
+		"edge [fontname=Helvetica];",
+		graph,
+		"}",
+		"",
+	})
+}
+
+func (dotSyntax) node(id string, shape NodeShape, label string) string {
+	attrs := "shape=box"
+	switch shape {
+	case ShapeRhombus:
+		attrs = "shape=diamond"
+	case ShapeHexagon:
+		attrs = "shape=hexagon"
+	case ShapeCircle:
+		attrs = "shape=circle"
+	case ShapeStadium:
+		attrs = "shape=box, style=rounded"
+	}
+	return fmt.Sprintf("%s [label=%s, %s];", id, dotText(label), attrs)
+}
+
+func (dotSyntax) edge(from string, label string, to string, dashed bool) string {
+	if dashed {
+		return fmt.Sprintf("%s -> %s [style=dashed, arrowhead=none];", from, to)
+	}
+	if label == "" {
+		return fmt.Sprintf("%s -> %s;", from, to)
+	}
+	return fmt.Sprintf("%s -> %s [label=%s];", from, to, dotText(label))
+}
+
+func (dotSyntax) cluster(id string, label string, stmt string) string {
+	return buildStmts([]string{
+		fmt.Sprintf("subgraph cluster_%s {", id),
+		fmt.Sprintf("label=%s;", dotText(label)),
+		stmt,
+		"}",
+	})
+}
+
+// dotText quotes a text for DOT language and breaks lines.
+func dotText(text string) string {
+	return `"` + strings.NewReplacer(`"`, `\"`, "\n", `\n`).Replace(text) + `"`
+}
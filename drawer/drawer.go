@@ -26,6 +26,8 @@ type Drawer interface {
 }
 
 type drawer struct {
+	drawOptions
+	*nestedFlows
 	drawers map[string]Generator
 }
 
@@ -40,10 +42,12 @@ func (d drawer) Register(apiExtType string, c Generator) error {
 	return nil
 }
 
-// Initializes a new Drawer
-func NewDrawer() Drawer {
+// Initializes a new Drawer with options
+func NewDrawer(opts ...Option) Drawer {
 	return &drawer{
-		drawers: Default(),
+		drawOptions: newDrawOptions(opts),
+		nestedFlows: &nestedFlows{},
+		drawers:     Default(),
 	}
 }
 
@@ -77,6 +81,11 @@ func Default() map[string]Generator {
 		"SimpleComponent":   DrawSimple,
 		"CaseComponent":     DrawCase,
 		"WrapComponent":     DrawWrap,
+		"ParallelComponent": DrawParallel,
+		"RetryComponent":    DrawRetry,
+		"TimeoutComponent":  DrawTimeout,
+		"ForEachComponent":  DrawForEach,
+		"IfComponent":       DrawIf,
 	}
 }
 
@@ -125,7 +134,11 @@ func DrawDecision(d Drawer, c types.Component) (ComponentStmt, error) {
 		if index > 0 {
 			caseStmtType = "elseif"
 		}
-		stmtBlock = append(stmtBlock, fmt.Sprintf("%s (%s equals %s) then (yes)", caseStmtType, tagType, fields.GetTypeStrName(dCase.Tag)))
+		tags := make([]string, len(dCase.Tags()))
+		for i, tag := range dCase.Tags() {
+			tags[i] = fields.GetTypeStrName(tag)
+		}
+		stmtBlock = append(stmtBlock, fmt.Sprintf("%s (%s equals %s) then (yes)", caseStmtType, tagType, strings.Join(tags, " or ")))
 		caseStmt, err := DrawCase(d, dCase)
 		if err != nil {
 			return nil, err
@@ -136,6 +149,17 @@ func DrawDecision(d Drawer, c types.Component) (ComponentStmt, error) {
 		}
 	}
 
+	if dComponent.Default != nil {
+		defaultStmt, err := DrawCase(d, dComponent.Default)
+		if err != nil {
+			return nil, err
+		}
+		stmtBlock = append(stmtBlock, "else (default)", defaultStmt.Stmt())
+		if defaultStmt.ReturnError() && !dStmt.returnErr {
+			dStmt.returnErr = true
+		}
+	}
+
 	if dStmt.returnErr {
 		stmtBlock = append(stmtBlock, drawFailureStmts(fmt.Sprintf("failure decision %s", tagType), failureStmt)...)
 	}
@@ -152,9 +176,35 @@ func DrawSimple(d Drawer, c types.Component) (ComponentStmt, error) {
 		return nil, errors.Errorf("can't draw component %s", c.Name())
 	}
 
+	if flow, ok := nestedFlow(d, sComponent); ok {
+		flowStmt, err := drawNestedFlow(d, flow)
+		if err != nil {
+			return nil, err
+		}
+		return &componentStmt{
+			returnErr: flowStmt.ReturnError(),
+			stmt: buildStmts([]string{
+				fmt.Sprintf("partition \"%s\" {", flow.Name.Name),
+				flowStmt.Stmt(),
+				"}",
+			}),
+		}, nil
+	}
+
 	stmt := &componentStmt{
 		stmt: fmt.Sprintf(":%s;", sComponent.Name()),
 	}
+	notes := stepAnnotations(d, sComponent)
+	if sComponent.Compensate != nil {
+		notes = append([]string{fmt.Sprintf("compensate %s", sComponent.Compensate.Name())}, notes...)
+	}
+	switch len(notes) {
+	case 0:
+	case 1:
+		stmt.stmt = buildStmts([]string{stmt.stmt, fmt.Sprintf("note right: %s", notes[0])})
+	default:
+		stmt.stmt = buildStmts(append(append([]string{stmt.stmt, "note right"}, notes...), "end note"))
+	}
 
 	if sComponent.Output != nil {
 		for _, output := range sComponent.Output.List {
@@ -185,7 +235,211 @@ func DrawWrap(d Drawer, c types.Component) (ComponentStmt, error) {
 		components = append(components, wComponent.Success)
 	}
 
-	return d.DrawBlock(components, wComponent.Failure)
+	if wComponent.Finally == nil {
+		return d.DrawBlock(components, wComponent.Failure)
+	}
+
+	// The finally function is drawn as a common exit node after the failure function and the last step.
+	// As in generated code, it's called after steps which return its arguments. The loader checks
+	// that these steps can't fail, so every failure goes through the finally function.
+	var failureStmt ComponentStmt
+	if wComponent.Failure != nil {
+		var err error
+		failureStmt, err = DrawSimple(d, wComponent.Failure)
+		if err != nil {
+			return nil, err
+		}
+	}
+	finallyStmt := fmt.Sprintf(":finally %s;", wComponent.Finally.Name())
+	failureStmts := []string{finallyStmt}
+	if failureStmt != nil {
+		failureStmts = []string{failureStmt.Stmt(), finallyStmt}
+	}
+
+	index := finallyIndex(components, wComponent.Finally)
+	beforeFinallyStmt, err := drawBlock(d, components[:index], failureStmt)
+	if err != nil {
+		return nil, err
+	}
+	afterFinallyStmt, err := drawBlock(d, components[index:], &componentStmt{stmt: buildStmts(failureStmts)})
+	if err != nil {
+		return nil, err
+	}
+
+	stmts := make([]string, 0)
+	if index > 0 {
+		stmts = append(stmts, beforeFinallyStmt.Stmt())
+	}
+	if index < len(components) {
+		stmts = append(stmts, afterFinallyStmt.Stmt())
+	}
+	stmts = append(stmts, finallyStmt)
+
+	return &componentStmt{
+		returnErr: beforeFinallyStmt.ReturnError() || afterFinallyStmt.ReturnError(),
+		stmt:      buildStmts(stmts),
+	}, nil
+}
+
+// finallyIndex returns an index of the first component after steps which return arguments of the finally function.
+func finallyIndex(components []types.Component, finally *types.SimpleComponent) int {
+	var index int
+	if finally.Input == nil {
+		return index
+	}
+	for componentIndex, component := range components {
+		simple, ok := component.(*types.SimpleComponent)
+		if !ok || simple.Output == nil {
+			continue
+		}
+		for _, input := range finally.Input.List {
+			if fields.FindFieldWithType(simple.Output.List, input.Type) != nil {
+				index = componentIndex + 1
+			}
+		}
+	}
+	return index
+}
+
+// DrawParallel converts component with type types.ParallelComponent to a statement
+func DrawParallel(d Drawer, c types.Component) (ComponentStmt, error) {
+	pComponent, ok := c.(*types.ParallelComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	pStmt := &componentStmt{}
+	stmtBlock := make([]string, 0)
+
+	for index, child := range pComponent.Children {
+		forkStmtType := "fork"
+		if index > 0 {
+			forkStmtType = "fork again"
+		}
+		stmtBlock = append(stmtBlock, forkStmtType)
+		childStmt, err := d.DrawComponent(child)
+		if err != nil {
+			return nil, err
+		}
+		stmtBlock = append(stmtBlock, childStmt.Stmt())
+		if childStmt.ReturnError() && !pStmt.returnErr {
+			pStmt.returnErr = true
+		}
+	}
+	stmtBlock = append(stmtBlock, "end fork")
+
+	pStmt.stmt = buildStmts(stmtBlock)
+	return pStmt, nil
+}
+
+// DrawRetry converts component with type types.RetryComponent to a statement
+func DrawRetry(d Drawer, c types.Component) (ComponentStmt, error) {
+	rComponent, ok := c.(*types.RetryComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	childStmt, err := d.DrawComponent(rComponent.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := fmt.Sprintf("error and attempt < %s", fields.GetTypeStrName(rComponent.Attempts))
+	if rComponent.RetryIf != nil {
+		cond = fmt.Sprintf("%s and %s", cond, rComponent.RetryIf.Name())
+	}
+
+	return &componentStmt{
+		returnErr: true,
+		stmt: buildStmts([]string{
+			"repeat",
+			childStmt.Stmt(),
+			fmt.Sprintf("repeat while (%s) is (yes)", cond),
+		}),
+	}, nil
+}
+
+// DrawTimeout converts component with type types.TimeoutComponent to a statement
+func DrawTimeout(d Drawer, c types.Component) (ComponentStmt, error) {
+	tComponent, ok := c.(*types.TimeoutComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	childStmt, err := d.DrawBlock(tComponent.Children, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &componentStmt{
+		returnErr: childStmt.ReturnError(),
+		stmt: buildStmts([]string{
+			fmt.Sprintf("partition \"%s\" {", tComponent.Name()),
+			childStmt.Stmt(),
+			"}",
+		}),
+	}, nil
+}
+
+// DrawForEach converts component with type types.ForEachComponent to a statement
+func DrawForEach(d Drawer, c types.Component) (ComponentStmt, error) {
+	feComponent, ok := c.(*types.ForEachComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	childStmt, err := d.DrawBlock(feComponent.Children, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := fmt.Sprintf("%s in %s", fields.GetTypeStrName(feComponent.ItemType), fields.GetTypeStrName(feComponent.SliceType))
+	if feComponent.Concurrency != nil {
+		cond = fmt.Sprintf("%s, concurrency %s", cond, fields.GetTypeStrName(feComponent.Concurrency))
+	}
+
+	return &componentStmt{
+		returnErr: childStmt.ReturnError(),
+		stmt: buildStmts([]string{
+			fmt.Sprintf("while (for each %s)", cond),
+			childStmt.Stmt(),
+			"endwhile",
+		}),
+	}, nil
+}
+
+// DrawIf converts component with type types.IfComponent to a statement
+func DrawIf(d Drawer, c types.Component) (ComponentStmt, error) {
+	iComponent, ok := c.(*types.IfComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	predicateStmt, err := DrawSimple(d, iComponent.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	thenStmt, err := d.DrawBlock(iComponent.Then, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	iStmt := &componentStmt{
+		returnErr: predicateStmt.ReturnError() || thenStmt.ReturnError(),
+	}
+	stmtBlock := []string{
+		fmt.Sprintf("if (%s) then (yes)", iComponent.Predicate.Name()),
+		thenStmt.Stmt(),
+	}
+	if len(iComponent.Else) > 0 {
+		elseStmt, err := d.DrawBlock(iComponent.Else, nil)
+		if err != nil {
+			return nil, err
+		}
+		if elseStmt.ReturnError() {
+			iStmt.returnErr = true
+		}
+		stmtBlock = append(stmtBlock, "else (no)", elseStmt.Stmt())
+	}
+	stmtBlock = append(stmtBlock, "endif")
+
+	iStmt.stmt = buildStmts(stmtBlock)
+	return iStmt, nil
 }
 
 // DrawComponent converts component with dynamic type to a statement.
@@ -228,8 +482,6 @@ func drawFailureStmts(msg string, stmt ComponentStmt) []string {
 
 // Helper for building multi-statement from an array of components with a specific error handler.
 func (d drawer) DrawBlock(components []types.Component, failure types.Component) (ComponentStmt, error) {
-	block := &componentStmt{}
-	stmts := make([]string, 0)
 	var failureStmt ComponentStmt
 	if failure != nil {
 		var err error
@@ -238,6 +490,12 @@ func (d drawer) DrawBlock(components []types.Component, failure types.Component)
 			return nil, err
 		}
 	}
+	return drawBlock(&d, components, failureStmt)
+}
+
+func drawBlock(d Drawer, components []types.Component, failureStmt ComponentStmt) (ComponentStmt, error) {
+	block := &componentStmt{}
+	stmts := make([]string, 0)
 	for _, component := range components {
 		cStmt, err := d.DrawComponent(component)
 		if err != nil {
@@ -0,0 +1,653 @@
+package drawer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// NodeShape is a shape of a node in a graph
+type NodeShape int
+
+// Shapes of nodes in a graph
+const (
+	ShapeRect NodeShape = iota
+	ShapeRhombus
+	ShapeHexagon
+	ShapeCircle
+	ShapeStadium
+)
+
+// GraphDrawer draws diagrams for business flows as graphs, for example Mermaid flowcharts
+// or Graphviz DOT graphs. Statements of a graph declare nodes, edges between nodes are collected
+// by the drawer and added at the end of the graph.
+type GraphDrawer interface {
+	Drawer
+
+	// Node generates an identifier for a new node and returns the identifier and a declaration of the node.
+	// A label can contain several lines.
+	Node(shape NodeShape, label string) (string, string)
+
+	// Edge connects two nodes with an optional label
+	Edge(from string, label string, to string)
+
+	// Cluster groups statements with a label
+	Cluster(label string, stmt string) string
+}
+
+// GraphExit is a node which is connected with the next statement by an edge with a label.
+type GraphExit struct {
+	Node  string
+	Label string
+}
+
+// GraphStmt represents a part of a graph. The statement has an entry node
+// which is connected with the previous statement, exits which are connected with the next statement
+// and nodes which return errors. If the entry is empty, the statement is skipped.
+type GraphStmt interface {
+	ComponentStmt
+	Entry() string
+	Exits() []GraphExit
+	Errors() []string
+}
+
+type graphStmt struct {
+	stmt  string
+	entry string
+	exits []GraphExit
+	errs  []string
+}
+
+// NewGraphStmt initializes a new statement for a graph.
+func NewGraphStmt(stmt string, entry string, exits []GraphExit, errs []string) GraphStmt {
+	return &graphStmt{
+		stmt:  stmt,
+		entry: entry,
+		exits: exits,
+		errs:  errs,
+	}
+}
+
+func (g graphStmt) Stmt() string {
+	return g.stmt
+}
+
+func (g graphStmt) ReturnError() bool {
+	return len(g.errs) > 0
+}
+
+func (g graphStmt) Entry() string {
+	return g.entry
+}
+
+func (g graphStmt) Exits() []GraphExit {
+	return g.exits
+}
+
+func (g graphStmt) Errors() []string {
+	return g.errs
+}
+
+// graphSyntax converts nodes, edges and clusters to a specific format of graphs.
+type graphSyntax interface {
+	ext() string
+	wrap(flowName string, graph string) string
+	node(id string, shape NodeShape, label string) string
+	edge(from string, label string, to string, dashed bool) string
+	cluster(id string, label string, stmt string) string
+}
+
+type graphDrawer struct {
+	drawOptions
+	*nestedFlows
+	syntax       graphSyntax
+	drawers      map[string]Generator
+	nodesCounter int
+	edges        []string
+}
+
+// Initializes a new Drawer for Mermaid flowcharts with options
+func NewMermaidDrawer(opts ...Option) GraphDrawer {
+	return &graphDrawer{
+		drawOptions: newDrawOptions(opts),
+		nestedFlows: &nestedFlows{},
+		syntax:      mermaidSyntax{},
+		drawers:     GraphDefault(),
+	}
+}
+
+// Initializes a new Drawer for Graphviz DOT graphs with options
+func NewDotDrawer(opts ...Option) GraphDrawer {
+	return &graphDrawer{
+		drawOptions: newDrawOptions(opts),
+		nestedFlows: &nestedFlows{},
+		syntax:      dotSyntax{},
+		drawers:     GraphDefault(),
+	}
+}
+
+// GraphDefault returns default generators for graphs.
+// It's possible to build custom map of generators or/and reuse existing.
+func GraphDefault() map[string]Generator {
+	return map[string]Generator{
+		"DecisionComponent": DrawGraphDecision,
+		"SimpleComponent":   DrawGraphSimple,
+		"CaseComponent":     DrawGraphCase,
+		"WrapComponent":     DrawGraphWrap,
+		"ParallelComponent": DrawGraphParallel,
+		"RetryComponent":    DrawGraphRetry,
+		"TimeoutComponent":  DrawGraphTimeout,
+		"ForEachComponent":  DrawGraphForEach,
+		"IfComponent":       DrawGraphIf,
+	}
+}
+
+// Register is a method for adding a new generator for a custom component type
+func (d *graphDrawer) Register(apiExtType string, c Generator) error {
+	_, ok := d.drawers[apiExtType]
+	if ok {
+		return errors.Errorf("api method %s already registered", apiExtType)
+	}
+
+	d.drawers[apiExtType] = c
+	return nil
+}
+
+func (d *graphDrawer) Node(shape NodeShape, label string) (string, string) {
+	d.nodesCounter++
+	id := fmt.Sprintf("n%d", d.nodesCounter)
+	return id, d.syntax.node(id, shape, label)
+}
+
+func (d *graphDrawer) Edge(from string, label string, to string) {
+	d.edges = append(d.edges, d.syntax.edge(from, label, to, false))
+}
+
+func (d *graphDrawer) Cluster(label string, stmt string) string {
+	d.nodesCounter++
+	return d.syntax.cluster(fmt.Sprintf("c%d", d.nodesCounter), label, stmt)
+}
+
+// Ext returns an extension of files with diagrams
+func (d *graphDrawer) Ext() string {
+	return d.syntax.ext()
+}
+
+// Wrap adds a header and a footer of a graph
+func (d *graphDrawer) Wrap(flowName string, graph string) string {
+	return d.syntax.wrap(flowName, graph)
+}
+
+// DrawComponent converts component with dynamic type to a statement.
+// DrawComponent uses a generator for a component by type. If the generator is not found
+// then the function returns an error.
+func (d *graphDrawer) DrawComponent(component types.Component) (ComponentStmt, error) {
+	cType := reflect.TypeOf(component).String()
+	dotIndex := strings.Index(cType, ".")
+
+	if dotIndex != -1 {
+		cType = string([]byte(cType)[dotIndex+1:])
+	}
+
+	handler, ok := d.drawers[cType]
+
+	if !ok {
+		return nil, errors.Errorf("unsupported type %s", cType)
+	}
+
+	return handler(d, component)
+}
+
+// DrawBlock connects statements of components one by one. If a failure component is set,
+// then nodes which return errors are connected with the failure component.
+func (d *graphDrawer) DrawBlock(components []types.Component, failure types.Component) (ComponentStmt, error) {
+	block := &graphStmt{}
+	stmts := make([]string, 0)
+	for _, component := range components {
+		cStmt, err := drawGraphComponent(d, component)
+		if err != nil {
+			return nil, err
+		}
+		if cStmt.Entry() == "" {
+			continue
+		}
+		stmts = append(stmts, cStmt.Stmt())
+		if block.entry == "" {
+			block.entry = cStmt.Entry()
+		} else {
+			connectGraphExits(d, block.exits, cStmt.Entry())
+		}
+		block.exits = cStmt.Exits()
+		block.errs = append(block.errs, cStmt.Errors()...)
+	}
+
+	if failure != nil && len(block.errs) > 0 {
+		failureStmt, err := drawGraphComponent(d, failure)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, failureStmt.Stmt())
+		connectGraphErrors(d, block.errs, failureStmt.Entry())
+		block.errs = exitNodes(failureStmt.Exits())
+	}
+
+	block.stmt = buildStmts(stmts)
+	return block, nil
+}
+
+// DrawFlow generates a graph by array of components and an error handler.
+// The graph doesn't contain a header, because the header depends on a name of the flow.
+func (d *graphDrawer) DrawFlow(components []types.Component, failure types.Component) (string, error) {
+	d.nodesCounter = 0
+	d.edges = nil
+
+	stmts := []string{
+		d.syntax.node("start", ShapeCircle, "start"),
+		d.syntax.node("stop", ShapeCircle, "end"),
+	}
+	fStmt, err := d.DrawBlock(components, failure)
+	if err != nil {
+		return "", err
+	}
+	blockStmt := fStmt.(GraphStmt)
+	stmts = append(stmts, blockStmt.Stmt())
+
+	if blockStmt.Entry() == "" {
+		d.Edge("start", "", "stop")
+	} else {
+		d.Edge("start", "", blockStmt.Entry())
+		connectGraphExits(d, blockStmt.Exits(), "stop")
+	}
+	if blockStmt.ReturnError() {
+		stmts = append(stmts, d.syntax.node("fail", ShapeCircle, "failure"))
+		connectGraphErrors(d, blockStmt.Errors(), "fail")
+	}
+
+	stmts = append(stmts, d.edges...)
+	return buildStmts(stmts), nil
+}
+
+// DrawGraphSimple converts a component with type types.SimpleComponent to a statement
+func DrawGraphSimple(d Drawer, c types.Component) (ComponentStmt, error) {
+	sComponent, ok := c.(*types.SimpleComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if flow, ok := nestedFlow(d, sComponent); ok {
+		flowStmt, err := drawNestedFlow(d, flow)
+		if err != nil {
+			return nil, err
+		}
+		block := flowStmt.(GraphStmt)
+		if block.Entry() != "" {
+			return NewGraphStmt(gd.Cluster(flow.Name.Name, block.Stmt()), block.Entry(), block.Exits(), block.Errors()), nil
+		}
+	}
+
+	label := buildStmts(append([]string{sComponent.Name().Name}, stepAnnotations(d, sComponent)...))
+	id, decl := gd.Node(ShapeRect, label)
+	stmts := []string{decl}
+	if sComponent.Compensate != nil {
+		compensateID, compensateDecl := gd.Node(ShapeStadium, fmt.Sprintf("compensate %s", sComponent.Compensate.Name()))
+		stmts = append(stmts, compensateDecl)
+		gd.edges = append(gd.edges, gd.syntax.edge(id, "", compensateID, true))
+	}
+
+	var errs []string
+	if returnsError(sComponent) {
+		errs = []string{id}
+	}
+	return NewGraphStmt(buildStmts(stmts), id, []GraphExit{{Node: id}}, errs), nil
+}
+
+// DrawGraphCase converts a component with type types.CaseComponent to a statement
+func DrawGraphCase(d Drawer, c types.Component) (ComponentStmt, error) {
+	caseComponent, ok := c.(*types.CaseComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	return d.DrawBlock(caseComponent.Children, nil)
+}
+
+// DrawGraphDecision converts a component with type types.DecisionComponent to a statement
+func DrawGraphDecision(d Drawer, c types.Component) (ComponentStmt, error) {
+	dComponent, ok := c.(*types.DecisionComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+
+	id, decl := gd.Node(ShapeRhombus, fields.GetTypeStrName(dComponent.TagType))
+	dStmt := &graphStmt{entry: id}
+	stmts := []string{decl}
+
+	drawCase := func(label string, caseComponent types.Component) error {
+		caseStmt, err := drawGraphComponent(d, caseComponent)
+		if err != nil {
+			return err
+		}
+		if caseStmt.Entry() == "" {
+			dStmt.exits = append(dStmt.exits, GraphExit{Node: id, Label: label})
+			return nil
+		}
+		stmts = append(stmts, caseStmt.Stmt())
+		gd.Edge(id, label, caseStmt.Entry())
+		dStmt.exits = append(dStmt.exits, caseStmt.Exits()...)
+		dStmt.errs = append(dStmt.errs, caseStmt.Errors()...)
+		return nil
+	}
+
+	for _, dCase := range dComponent.Cases {
+		tags := make([]string, len(dCase.Tags()))
+		for i, tag := range dCase.Tags() {
+			tags[i] = fields.GetTypeStrName(tag)
+		}
+		if err := drawCase(strings.Join(tags, " or "), dCase); err != nil {
+			return nil, err
+		}
+	}
+
+	if dComponent.Default != nil {
+		if err := drawCase("default", dComponent.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	if dComponent.Failure != nil && len(dStmt.errs) > 0 {
+		failureStmt, err := drawGraphComponent(d, dComponent.Failure)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't render failure component %s", dComponent.Failure.Name())
+		}
+		stmts = append(stmts, failureStmt.Stmt())
+		connectGraphErrors(gd, dStmt.errs, failureStmt.Entry())
+		dStmt.errs = exitNodes(failureStmt.Exits())
+	}
+
+	dStmt.stmt = buildStmts(stmts)
+	return dStmt, nil
+}
+
+// DrawGraphWrap converts a component with type types.WrapComponent to a cluster of statements.
+// The finally function is drawn as a common exit node after the failure function and the last step.
+// Steps before the finally function is deferred can't fail, so all errors of the block lead to it.
+func DrawGraphWrap(d Drawer, c types.Component) (ComponentStmt, error) {
+	wComponent, ok := c.(*types.WrapComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	components := make([]types.Component, 0)
+
+	if wComponent.Before != nil {
+		components = append(components, wComponent.Before)
+	}
+
+	components = append(components, wComponent.Children...)
+
+	if wComponent.Success != nil {
+		components = append(components, wComponent.Success)
+	}
+
+	var failure types.Component
+	if wComponent.Failure != nil {
+		failure = wComponent.Failure
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+	blockStmt, err := d.DrawBlock(components, failure)
+	if err != nil {
+		return nil, err
+	}
+
+	block := blockStmt.(GraphStmt)
+	wStmt := &graphStmt{
+		stmt:  block.Stmt(),
+		entry: block.Entry(),
+		exits: block.Exits(),
+		errs:  block.Errors(),
+	}
+	if wComponent.Finally != nil {
+		id, decl := gd.Node(ShapeRect, fmt.Sprintf("finally %s", wComponent.Finally.Name()))
+		wStmt.stmt = buildStmts([]string{block.Stmt(), decl})
+		wStmt.exits = []GraphExit{{Node: id}}
+		if block.Entry() == "" {
+			wStmt.entry = id
+		} else {
+			connectGraphExits(gd, block.Exits(), id)
+		}
+		if block.ReturnError() {
+			connectGraphErrors(gd, block.Errors(), id)
+			wStmt.errs = []string{id}
+		}
+	}
+	if wStmt.entry != "" {
+		wStmt.stmt = gd.Cluster(wComponent.Name().Name, wStmt.stmt)
+	}
+	return wStmt, nil
+}
+
+// DrawGraphParallel converts a component with type types.ParallelComponent to a statement
+func DrawGraphParallel(d Drawer, c types.Component) (ComponentStmt, error) {
+	pComponent, ok := c.(*types.ParallelComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+
+	forkID, forkDecl := gd.Node(ShapeHexagon, "fork")
+	joinID, joinDecl := gd.Node(ShapeHexagon, "end fork")
+	pStmt := &graphStmt{
+		entry: forkID,
+		exits: []GraphExit{{Node: joinID}},
+	}
+	stmts := []string{forkDecl}
+	for _, child := range pComponent.Children {
+		childStmt, err := drawGraphComponent(d, child)
+		if err != nil {
+			return nil, err
+		}
+		if childStmt.Entry() == "" {
+			continue
+		}
+		stmts = append(stmts, childStmt.Stmt())
+		gd.Edge(forkID, "", childStmt.Entry())
+		connectGraphExits(gd, childStmt.Exits(), joinID)
+		pStmt.errs = append(pStmt.errs, childStmt.Errors()...)
+	}
+	stmts = append(stmts, joinDecl)
+
+	pStmt.stmt = buildStmts(stmts)
+	return pStmt, nil
+}
+
+// DrawGraphRetry converts a component with type types.RetryComponent to a statement.
+// Nodes which return errors are connected with a condition of the retry, which repeats the child.
+func DrawGraphRetry(d Drawer, c types.Component) (ComponentStmt, error) {
+	rComponent, ok := c.(*types.RetryComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+	childStmt, err := drawGraphComponent(d, rComponent.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := fmt.Sprintf("attempt < %s", fields.GetTypeStrName(rComponent.Attempts))
+	if rComponent.RetryIf != nil {
+		cond = fmt.Sprintf("%s and %s", cond, rComponent.RetryIf.Name())
+	}
+	id, decl := gd.Node(ShapeRhombus, cond)
+	connectGraphErrors(gd, childStmt.Errors(), id)
+	gd.Edge(id, "yes", childStmt.Entry())
+
+	return NewGraphStmt(buildStmts([]string{childStmt.Stmt(), decl}), childStmt.Entry(), childStmt.Exits(), []string{id}), nil
+}
+
+// DrawGraphTimeout converts a component with type types.TimeoutComponent to a statement
+func DrawGraphTimeout(d Drawer, c types.Component) (ComponentStmt, error) {
+	tComponent, ok := c.(*types.TimeoutComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+	childStmt, err := d.DrawBlock(tComponent.Children, nil)
+	if err != nil {
+		return nil, err
+	}
+	block := childStmt.(GraphStmt)
+	if block.Entry() == "" {
+		return block, nil
+	}
+
+	return NewGraphStmt(gd.Cluster(tComponent.Name().Name, block.Stmt()), block.Entry(), block.Exits(), block.Errors()), nil
+}
+
+// DrawGraphForEach converts a component with type types.ForEachComponent to a statement
+func DrawGraphForEach(d Drawer, c types.Component) (ComponentStmt, error) {
+	feComponent, ok := c.(*types.ForEachComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := fmt.Sprintf("for each %s in %s", fields.GetTypeStrName(feComponent.ItemType), fields.GetTypeStrName(feComponent.SliceType))
+	if feComponent.Concurrency != nil {
+		cond = fmt.Sprintf("%s, concurrency %s", cond, fields.GetTypeStrName(feComponent.Concurrency))
+	}
+	id, decl := gd.Node(ShapeHexagon, cond)
+
+	childStmt, err := d.DrawBlock(feComponent.Children, nil)
+	if err != nil {
+		return nil, err
+	}
+	block := childStmt.(GraphStmt)
+	if block.Entry() != "" {
+		gd.Edge(id, "", block.Entry())
+		connectGraphExits(gd, block.Exits(), id)
+	}
+
+	return NewGraphStmt(buildStmts([]string{decl, block.Stmt()}), id, []GraphExit{{Node: id, Label: "done"}}, block.Errors()), nil
+}
+
+// DrawGraphIf converts a component with type types.IfComponent to a statement
+func DrawGraphIf(d Drawer, c types.Component) (ComponentStmt, error) {
+	iComponent, ok := c.(*types.IfComponent)
+	if !ok {
+		return nil, errors.Errorf("can't draw component %s", c.Name())
+	}
+	gd, err := graphDrawerFrom(d)
+	if err != nil {
+		return nil, err
+	}
+
+	id, decl := gd.Node(ShapeRhombus, iComponent.Predicate.Name().Name)
+	iStmt := &graphStmt{entry: id}
+	stmts := []string{decl}
+	if returnsError(iComponent.Predicate) {
+		iStmt.errs = append(iStmt.errs, id)
+	}
+
+	branches := []struct {
+		label      string
+		components []types.Component
+	}{
+		{label: "yes", components: iComponent.Then},
+		{label: "no", components: iComponent.Else},
+	}
+	for _, branch := range branches {
+		branchStmt, err := d.DrawBlock(branch.components, nil)
+		if err != nil {
+			return nil, err
+		}
+		block := branchStmt.(GraphStmt)
+		if block.Entry() == "" {
+			iStmt.exits = append(iStmt.exits, GraphExit{Node: id, Label: branch.label})
+			continue
+		}
+		stmts = append(stmts, block.Stmt())
+		gd.Edge(id, branch.label, block.Entry())
+		iStmt.exits = append(iStmt.exits, block.Exits()...)
+		iStmt.errs = append(iStmt.errs, block.Errors()...)
+	}
+
+	iStmt.stmt = buildStmts(stmts)
+	return iStmt, nil
+}
+
+func returnsError(sComponent *types.SimpleComponent) bool {
+	if sComponent.Output == nil {
+		return false
+	}
+	for _, output := range sComponent.Output.List {
+		if fields.GetTypeStrName(output.Type) == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+func graphDrawerFrom(d Drawer) (*graphDrawer, error) {
+	gd, ok := d.(*graphDrawer)
+	if !ok {
+		return nil, errors.New("graph generators can be used only with drawers from NewMermaidDrawer and NewDotDrawer")
+	}
+	return gd, nil
+}
+
+func drawGraphComponent(d Drawer, c types.Component) (GraphStmt, error) {
+	stmt, err := d.DrawComponent(c)
+	if err != nil {
+		return nil, err
+	}
+	mStmt, ok := stmt.(GraphStmt)
+	if !ok {
+		return nil, errors.Errorf("statement of component %s is not a graph statement", c.Name())
+	}
+	return mStmt, nil
+}
+
+func connectGraphExits(d GraphDrawer, exits []GraphExit, to string) {
+	for _, exit := range exits {
+		d.Edge(exit.Node, exit.Label, to)
+	}
+}
+
+func connectGraphErrors(d GraphDrawer, errs []string, to string) {
+	for _, node := range errs {
+		d.Edge(node, "error", to)
+	}
+}
+
+func exitNodes(exits []GraphExit) []string {
+	nodes := make([]string, len(exits))
+	for i, exit := range exits {
+		nodes[i] = exit.Node
+	}
+	return nodes
+}
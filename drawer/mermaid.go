@@ -0,0 +1,55 @@
+package drawer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mermaidSyntax draws graphs as Mermaid flowcharts
+type mermaidSyntax struct{}
+
+func (mermaidSyntax) ext() string {
+	return "mmd"
+}
+
+func (mermaidSyntax) wrap(flowName string, graph string) string {
+	return fmt.Sprintf("---\ntitle: %s\n---\nflowchart TD\n%s\n", flowName, graph)
+}
+
+func (mermaidSyntax) node(id string, shape NodeShape, label string) string {
+	format := `%s["%s"]`
+	switch shape {
+	case ShapeRhombus:
+		format = `%s{"%s"}`
+	case ShapeHexagon:
+		format = `%s{{"%s"}}`
+	case ShapeCircle:
+		format = `%s(("%s"))`
+	case ShapeStadium:
+		format = `%s(["%s"])`
+	}
+	return fmt.Sprintf(format, id, mermaidText(label))
+}
+
+func (mermaidSyntax) edge(from string, label string, to string, dashed bool) string {
+	if dashed {
+		return fmt.Sprintf("%s -.- %s", from, to)
+	}
+	if label == "" {
+		return fmt.Sprintf("%s --> %s", from, to)
+	}
+	return fmt.Sprintf("%s -->|\"%s\"| %s", from, mermaidText(label), to)
+}
+
+func (mermaidSyntax) cluster(id string, label string, stmt string) string {
+	return buildStmts([]string{
+		fmt.Sprintf("subgraph %s [\"%s\"]", id, mermaidText(label)),
+		stmt,
+		"end",
+	})
+}
+
+// mermaidText escapes quotes in a text of a label and breaks lines.
+func mermaidText(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(text)
+}
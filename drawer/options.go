@@ -0,0 +1,139 @@
+package drawer
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/types"
+)
+
+// Option configures a drawer
+type Option func(o *drawOptions)
+
+type drawOptions struct {
+	types            bool
+	deps             bool
+	nestedFlowsDepth int
+}
+
+// WithTypes annotates each step with types which the step consumes and produces
+func WithTypes() Option {
+	return func(o *drawOptions) {
+		o.types = true
+	}
+}
+
+// WithDeps annotates each step with types of its dependencies
+func WithDeps() Option {
+	return func(o *drawOptions) {
+		o.deps = true
+	}
+}
+
+func newDrawOptions(opts []Option) drawOptions {
+	o := drawOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o drawOptions) options() drawOptions {
+	return o
+}
+
+// stepAnnotations returns lines with types of a step, which are enabled by options of a drawer.
+// Custom drawers don't have options, so their steps are drawn without annotations.
+func stepAnnotations(d Drawer, sComponent *types.SimpleComponent) []string {
+	withOptions, ok := d.(interface{ options() drawOptions })
+	if !ok {
+		return nil
+	}
+	o := withOptions.options()
+
+	lines := make([]string, 0)
+	if o.types {
+		if input := fieldTypes(sComponent.Input); input != "" {
+			lines = append(lines, "in: "+input)
+		}
+		if output := fieldTypes(sComponent.Output); output != "" {
+			lines = append(lines, "out: "+output)
+		}
+	}
+	if o.deps {
+		if deps := fieldTypes(sComponent.Deps); deps != "" {
+			lines = append(lines, "deps: "+deps)
+		}
+	}
+	return lines
+}
+
+// fieldTypes joins types of fields, a field with several names is repeated for each name.
+func fieldTypes(list *ast.FieldList) string {
+	if list == nil {
+		return ""
+	}
+	fieldTypes := make([]string, 0, len(list.List))
+	for _, field := range list.List {
+		t := fields.GetTypeStrName(field.Type)
+		fieldTypes = append(fieldTypes, t)
+		for i := 1; i < len(field.Names); i++ {
+			fieldTypes = append(fieldTypes, t)
+		}
+	}
+	return strings.Join(fieldTypes, ", ")
+}
+
+// WithNestedFlows draws flows which are used as steps by other flows inline,
+// a depth limits a number of nested levels. By default nested flows are drawn as steps.
+func WithNestedFlows(depth int) Option {
+	return func(o *drawOptions) {
+		o.nestedFlowsDepth = depth
+	}
+}
+
+// nestedFlows stores flows of a package for drawing them inline.
+type nestedFlows struct {
+	flows map[string]types.Flow
+	depth int
+}
+
+// SetFlows sets flows of a package, which can be drawn inline
+func (n *nestedFlows) SetFlows(flows []types.Flow) {
+	n.flows = make(map[string]types.Flow, len(flows))
+	for _, flow := range flows {
+		n.flows[flow.Name.Name] = flow
+	}
+}
+
+func (n *nestedFlows) nested() *nestedFlows {
+	return n
+}
+
+// nestedFlow returns a flow which is used as a step, if the flow must be drawn inline.
+func nestedFlow(d Drawer, sComponent *types.SimpleComponent) (types.Flow, bool) {
+	withOptions, ok := d.(interface {
+		options() drawOptions
+		nested() *nestedFlows
+	})
+	if !ok || sComponent.Package != nil || sComponent.Receiver != nil || sComponent.OriginalFuncName == nil {
+		return types.Flow{}, false
+	}
+	n := withOptions.nested()
+	if n.depth >= withOptions.options().nestedFlowsDepth {
+		return types.Flow{}, false
+	}
+	flow, ok := n.flows[sComponent.OriginalFuncName.Name]
+	return flow, ok
+}
+
+// drawNestedFlow draws components of a nested flow with its failure handler.
+func drawNestedFlow(d Drawer, flow types.Flow) (ComponentStmt, error) {
+	n := d.(interface{ nested() *nestedFlows }).nested()
+	n.depth++
+	defer func() {
+		n.depth--
+	}()
+	return d.DrawBlock(flow.Components, flow.Failure)
+}
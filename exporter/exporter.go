@@ -0,0 +1,443 @@
+package exporter
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/token"
+	goTypes "go/types"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// Exporter converts business flows to a machine-readable JSON description
+type Exporter interface {
+	// ExportFlows takes business flows of a package and returns a JSON document
+	ExportFlows(string, *token.FileSet, []types.Flow) ([]byte, error)
+
+	// ExportComponent converts a component to a description
+	ExportComponent(types.Component) (*Component, error)
+
+	// Position returns a position of a node in a source file, it returns nil for unknown positions
+	Position(token.Pos) *Position
+
+	// Register is a method for adding a new generator for a custom component type
+	Register(string, Generator) error
+}
+
+// Generator converts a component to a description
+type Generator func(Exporter, types.Component) (*Component, error)
+
+type exporter struct {
+	generators map[string]Generator
+	fset       *token.FileSet
+}
+
+// Initializes a new Exporter
+func NewExporter() Exporter {
+	return &exporter{
+		generators: Default(),
+	}
+}
+
+// Default generators.
+// It's possible to build custom map of generators or/and reuse existing.
+func Default() map[string]Generator {
+	return map[string]Generator{
+		"DecisionComponent": ExportDecision,
+		"SimpleComponent":   ExportSimple,
+		"CaseComponent":     ExportCase,
+		"WrapComponent":     ExportWrap,
+		"ParallelComponent": ExportParallel,
+		"RetryComponent":    ExportRetry,
+		"TimeoutComponent":  ExportTimeout,
+		"ForEachComponent":  ExportForEach,
+		"IfComponent":       ExportIf,
+	}
+}
+
+// Register is a method for adding a new generator for a custom component type
+func (e *exporter) Register(apiExtType string, gen Generator) error {
+	_, ok := e.generators[apiExtType]
+	if ok {
+		return errors.Errorf("api method %s already registered", apiExtType)
+	}
+
+	e.generators[apiExtType] = gen
+	return nil
+}
+
+// ExportFlows builds a document with descriptions of flows and encodes it to JSON
+func (e *exporter) ExportFlows(pkgPath string, fset *token.FileSet, flows []types.Flow) ([]byte, error) {
+	e.fset = fset
+	doc := &Document{
+		SchemaVersion: SchemaVersion,
+		Package:       pkgPath,
+		Flows:         make([]*Flow, 0, len(flows)),
+	}
+	for _, flow := range flows {
+		components, err := ExportComponents(e, flow.Components)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't export flow %s", flow.Name.Name)
+		}
+		failure, err := exportOptional(e, flow.Failure)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't export flow %s", flow.Name.Name)
+		}
+		doc.Flows = append(doc.Flows, &Flow{
+			Name:       flow.Name.Name,
+			Position:   e.Position(flow.Name.Pos()),
+			Components: components,
+			Failure:    failure,
+		})
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// ExportComponent converts component with dynamic type to a description.
+// ExportComponent uses a generator for a component by type. If the generator is not found
+// then the function returns an error.
+func (e *exporter) ExportComponent(component types.Component) (*Component, error) {
+	cType := reflect.TypeOf(component).String()
+	dotIndex := strings.Index(cType, ".")
+
+	if dotIndex != -1 {
+		cType = string([]byte(cType)[dotIndex+1:])
+	}
+
+	gen, ok := e.generators[cType]
+	if !ok {
+		return nil, errors.Errorf("unsupported type %s", cType)
+	}
+
+	return gen(e, component)
+}
+
+func (e *exporter) Position(pos token.Pos) *Position {
+	if e.fset == nil || !pos.IsValid() {
+		return nil
+	}
+	position := e.fset.Position(pos)
+	return &Position{
+		File:   filepath.Base(position.Filename),
+		Line:   position.Line,
+		Column: position.Column,
+	}
+}
+
+// ExportComponents converts an array of components to descriptions
+func ExportComponents(e Exporter, components []types.Component) ([]*Component, error) {
+	result := make([]*Component, 0, len(components))
+	for _, component := range components {
+		c, err := e.ExportComponent(component)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// ExportSimple converts a component with type types.SimpleComponent to a description
+func ExportSimple(e Exporter, c types.Component) (*Component, error) {
+	sComponent, ok := c.(*types.SimpleComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+
+	component := &Component{
+		Kind:   StepKind,
+		Name:   sComponent.Name().Name,
+		Input:  exportFields(sComponent.Input),
+		Output: exportFields(sComponent.Output),
+		Deps:   exportFields(sComponent.Deps),
+		As:     sComponent.As,
+	}
+	if sComponent.OriginalFuncName != nil {
+		component.Func = stepFunc(sComponent)
+		component.Position = e.Position(sComponent.OriginalFuncName.Pos())
+	}
+	if sComponent.Compensate != nil {
+		compensate, err := ExportSimple(e, sComponent.Compensate)
+		if err != nil {
+			return nil, err
+		}
+		component.Compensate = compensate
+	}
+	return component, nil
+}
+
+// ExportCase converts a component with type types.CaseComponent to a description
+func ExportCase(e Exporter, c types.Component) (*Component, error) {
+	caseComponent, ok := c.(*types.CaseComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+	children, err := ExportComponents(e, caseComponent.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &Component{
+		Kind:     CaseKind,
+		Name:     caseComponent.Name().Name,
+		Children: children,
+	}
+	for _, tag := range caseComponent.Tags() {
+		component.Keys = append(component.Keys, exprString(tag))
+	}
+	if caseComponent.Tag != nil {
+		component.Position = e.Position(caseComponent.Tag.Pos())
+	}
+	return component, nil
+}
+
+// ExportDecision converts a component with type types.DecisionComponent to a description
+func ExportDecision(e Exporter, c types.Component) (*Component, error) {
+	dComponent, ok := c.(*types.DecisionComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+
+	component := &Component{
+		Kind:     DecisionKind,
+		Name:     dComponent.Name().Name,
+		Position: e.Position(dComponent.Tag.Pos()),
+		Tag:      exprString(dComponent.Tag),
+		TagType:  exprString(dComponent.TagType),
+	}
+	for _, dCase := range dComponent.Cases {
+		caseComponent, err := ExportCase(e, dCase)
+		if err != nil {
+			return nil, err
+		}
+		component.Cases = append(component.Cases, caseComponent)
+	}
+
+	var err error
+	if dComponent.Default != nil {
+		component.Default, err = ExportCase(e, dComponent.Default)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dComponent.Failure != nil {
+		component.Failure, err = ExportSimple(e, dComponent.Failure)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return component, nil
+}
+
+// ExportWrap converts a component with type types.WrapComponent to a description
+func ExportWrap(e Exporter, c types.Component) (*Component, error) {
+	wComponent, ok := c.(*types.WrapComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+	children, err := ExportComponents(e, wComponent.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &Component{
+		Kind:     WrapKind,
+		Name:     wComponent.Name().Name,
+		Children: children,
+	}
+	handlers := []struct {
+		handler *types.SimpleComponent
+		field   **Component
+	}{
+		{handler: wComponent.Before, field: &component.Before},
+		{handler: wComponent.Success, field: &component.Success},
+		{handler: wComponent.Failure, field: &component.Failure},
+		{handler: wComponent.Finally, field: &component.Finally},
+	}
+	for _, h := range handlers {
+		if h.handler == nil {
+			continue
+		}
+		*h.field, err = ExportSimple(e, h.handler)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return component, nil
+}
+
+// ExportParallel converts a component with type types.ParallelComponent to a description
+func ExportParallel(e Exporter, c types.Component) (*Component, error) {
+	pComponent, ok := c.(*types.ParallelComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+	children, err := ExportComponents(e, pComponent.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Component{
+		Kind:     ParallelKind,
+		Name:     pComponent.Name().Name,
+		Children: children,
+	}, nil
+}
+
+// ExportRetry converts a component with type types.RetryComponent to a description
+func ExportRetry(e Exporter, c types.Component) (*Component, error) {
+	rComponent, ok := c.(*types.RetryComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+	child, err := e.ExportComponent(rComponent.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &Component{
+		Kind:     RetryKind,
+		Name:     rComponent.Name().Name,
+		Attempts: exprString(rComponent.Attempts),
+		Backoff:  exprString(rComponent.Backoff),
+		Children: []*Component{child},
+	}
+	if rComponent.Attempts != nil {
+		component.Position = e.Position(rComponent.Attempts.Pos())
+	}
+	if rComponent.RetryIf != nil {
+		component.RetryIf, err = ExportSimple(e, rComponent.RetryIf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return component, nil
+}
+
+// ExportTimeout converts a component with type types.TimeoutComponent to a description
+func ExportTimeout(e Exporter, c types.Component) (*Component, error) {
+	tComponent, ok := c.(*types.TimeoutComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+	children, err := ExportComponents(e, tComponent.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Component{
+		Kind:     TimeoutKind,
+		Name:     tComponent.Name().Name,
+		Position: e.Position(tComponent.Duration.Pos()),
+		Duration: exprString(tComponent.Duration),
+		Children: children,
+	}, nil
+}
+
+// ExportForEach converts a component with type types.ForEachComponent to a description
+func ExportForEach(e Exporter, c types.Component) (*Component, error) {
+	feComponent, ok := c.(*types.ForEachComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+	children, err := ExportComponents(e, feComponent.Children)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Component{
+		Kind:        ForEachKind,
+		Name:        feComponent.Name().Name,
+		Position:    e.Position(feComponent.SliceType.Pos()),
+		SliceType:   exprString(feComponent.SliceType),
+		ItemType:    exprString(feComponent.ItemType),
+		Collect:     exprString(feComponent.Collect),
+		Concurrency: exprString(feComponent.Concurrency),
+		Children:    children,
+	}, nil
+}
+
+// ExportIf converts a component with type types.IfComponent to a description
+func ExportIf(e Exporter, c types.Component) (*Component, error) {
+	iComponent, ok := c.(*types.IfComponent)
+	if !ok {
+		return nil, errors.Errorf("can't export component %s", c.Name())
+	}
+	predicate, err := ExportSimple(e, iComponent.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	thenComponents, err := ExportComponents(e, iComponent.Then)
+	if err != nil {
+		return nil, err
+	}
+	elseComponents, err := ExportComponents(e, iComponent.Else)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Component{
+		Kind:      IfKind,
+		Name:      iComponent.Name().Name,
+		Position:  predicate.Position,
+		Predicate: predicate,
+		Then:      thenComponents,
+		Else:      elseComponents,
+	}, nil
+}
+
+// stepFunc returns an expression which declares a step in DSL, for example
+// payments.ChargeStep or (*PaymentService).Charge.
+func stepFunc(sComponent *types.SimpleComponent) string {
+	name := sComponent.OriginalFuncName.Name
+	switch {
+	case sComponent.Receiver != nil:
+		if _, isPtr := sComponent.Receiver.(*ast.StarExpr); isPtr {
+			return "(" + exprString(sComponent.Receiver) + ")." + name
+		}
+		return exprString(sComponent.Receiver) + "." + name
+	case sComponent.Package != nil:
+		return sComponent.Package.Name + "." + name
+	default:
+		return name
+	}
+}
+
+func exportOptional(e Exporter, c types.Component) (*Component, error) {
+	if c == nil || reflect.ValueOf(c).IsNil() {
+		return nil, nil
+	}
+	return e.ExportComponent(c)
+}
+
+// exportFields converts a list of fields to descriptions, a field with several names
+// is converted to several descriptions.
+func exportFields(list *ast.FieldList) []*Field {
+	if list == nil {
+		return nil
+	}
+	result := make([]*Field, 0, len(list.List))
+	for _, field := range list.List {
+		t := fields.GetTypeStrName(field.Type)
+		if len(field.Names) == 0 {
+			result = append(result, &Field{Type: t})
+			continue
+		}
+		for _, name := range field.Names {
+			result = append(result, &Field{Name: name.Name, Type: t})
+		}
+	}
+	return result
+}
+
+func exprString(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	return goTypes.ExprString(expr)
+}
@@ -0,0 +1,98 @@
+package exporter
+
+// SchemaVersion is a version of the JSON schema. It's increased on incompatible changes of the schema,
+// new optional fields are added without changing the version.
+const SchemaVersion = 1
+
+// Kinds of default components
+const (
+	StepKind     = "step"
+	CaseKind     = "case"
+	DecisionKind = "decision"
+	WrapKind     = "wrap"
+	ParallelKind = "parallel"
+	RetryKind    = "retry"
+	TimeoutKind  = "timeout"
+	ForEachKind  = "foreach"
+	IfKind       = "if"
+)
+
+// Document is a description of all business flows in a package.
+type Document struct {
+	SchemaVersion int     `json:"schemaVersion"`
+	Package       string  `json:"package"`
+	Flows         []*Flow `json:"flows"`
+}
+
+// Flow is a description of a business flow declared with effe.BuildFlow.
+type Flow struct {
+	Name       string       `json:"name"`
+	Position   *Position    `json:"position,omitempty"`
+	Components []*Component `json:"components"`
+	Failure    *Component   `json:"failure,omitempty"`
+}
+
+// Component is a description of a component. Fields are filled depending on a kind of the component,
+// for example only steps have inputs, outputs and dependencies.
+type Component struct {
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	Position *Position `json:"position,omitempty"`
+
+	// Fields of steps. Func is an expression which declares the step in DSL, for example payments.ChargeStep.
+	Func       string     `json:"func,omitempty"`
+	Input      []*Field   `json:"input,omitempty"`
+	Output     []*Field   `json:"output,omitempty"`
+	Deps       []*Field   `json:"deps,omitempty"`
+	As         string     `json:"as,omitempty"`
+	Compensate *Component `json:"compensate,omitempty"`
+
+	// Fields of cases and decisions. Keys are values of a tag for a case, a default case doesn't have keys.
+	Keys    []string     `json:"keys,omitempty"`
+	Tag     string       `json:"tag,omitempty"`
+	TagType string       `json:"tagType,omitempty"`
+	Cases   []*Component `json:"cases,omitempty"`
+	Default *Component   `json:"default,omitempty"`
+
+	// Fields of wraps. Failure is used by decisions too.
+	Before  *Component `json:"before,omitempty"`
+	Success *Component `json:"success,omitempty"`
+	Failure *Component `json:"failure,omitempty"`
+	Finally *Component `json:"finally,omitempty"`
+
+	// Fields of retries
+	Attempts string     `json:"attempts,omitempty"`
+	Backoff  string     `json:"backoff,omitempty"`
+	RetryIf  *Component `json:"retryIf,omitempty"`
+
+	// Fields of timeouts
+	Duration string `json:"duration,omitempty"`
+
+	// Fields of loops
+	SliceType   string `json:"sliceType,omitempty"`
+	ItemType    string `json:"itemType,omitempty"`
+	Collect     string `json:"collect,omitempty"`
+	Concurrency string `json:"concurrency,omitempty"`
+
+	// Fields of conditions. Then and Else are children of branches.
+	Predicate *Component   `json:"predicate,omitempty"`
+	Then      []*Component `json:"then,omitempty"`
+	Else      []*Component `json:"else,omitempty"`
+
+	// Children of wraps, parallels, retries, timeouts, loops and cases.
+	Children []*Component `json:"children,omitempty"`
+}
+
+// Field is an argument or a result of a function. Name is empty for unnamed fields.
+type Field struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// Position is a position of a component in a source file. File is a name of the file
+// without a directory, because all flows of a package are declared in one directory.
+type Position struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
@@ -50,8 +50,14 @@ func NewIdentWithType(t ast.Expr) *ast.Ident {
 
 // Searches a field with a specific type in an array. Returns a field or nil.
 func FindFieldWithType(fields []*ast.Field, t ast.Expr) *ast.Field {
+	return FindFieldWithTypeInfo(nil, fields, t)
+}
+
+// Works as FindFieldWithType, but types are compared by keys from GetTypeKey.
+func FindFieldWithTypeInfo(info *types.Info, fields []*ast.Field, t ast.Expr) *ast.Field {
+	key := GetTypeKey(info, t)
 	for _, f := range fields {
-		if GetTypeStrName(f.Type) == GetTypeStrName(t) {
+		if GetTypeKey(info, f.Type) == key {
 			return f
 		}
 	}
@@ -62,3 +68,98 @@ func FindFieldWithType(fields []*ast.Field, t ast.Expr) *ast.Field {
 func GetTypeStrName(t ast.Expr) string {
 	return types.ExprString(t)
 }
+
+// Represents a string by a type which the type checker infers for an expression.
+// Identical types have the same key, even if they are declared with an alias or
+// a package is imported with another name. If the type checker doesn't know
+// the expression, then GetTypeKey returns a string representation of the expression.
+func GetTypeKey(info *types.Info, t ast.Expr) string {
+	typ := typeOf(info, t)
+	if typ == nil {
+		return GetTypeStrName(t)
+	}
+	return types.TypeString(unalias(typ), nil)
+}
+
+// GetTypeName returns a name of a type as it's written in a source code with a name of
+// a package, for example *models.User. Types of the package, which is checked by the type
+// checker, are written without a name of the package. Aliases are replaced with actual types.
+func GetTypeName(info *types.Info, t ast.Expr) string {
+	typ := typeOf(info, t)
+	if typ == nil {
+		return GetTypeStrName(t)
+	}
+	pkg := packageOf(info)
+	return types.TypeString(unalias(typ), func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
+// packageOf returns a package which is checked by the type checker
+func packageOf(info *types.Info) *types.Package {
+	if info == nil {
+		return nil
+	}
+	for _, obj := range info.Defs {
+		if obj != nil && obj.Pkg() != nil {
+			return obj.Pkg()
+		}
+	}
+	return nil
+}
+
+// typeOf returns a type of an expression. Expressions built by Effe are not checked
+// by the type checker, so their types are built from types of nested expressions.
+func typeOf(info *types.Info, t ast.Expr) types.Type {
+	if info != nil {
+		if typ := info.TypeOf(t); typ != nil {
+			return typ
+		}
+	}
+
+	switch t := t.(type) {
+	case *ast.Ident:
+		if obj, ok := types.Universe.Lookup(t.Name).(*types.TypeName); ok {
+			return obj.Type()
+		}
+	case *ast.StarExpr:
+		if elem := typeOf(info, t.X); elem != nil {
+			return types.NewPointer(elem)
+		}
+	case *ast.ArrayType:
+		if elem := typeOf(info, t.Elt); elem != nil && t.Len == nil {
+			return types.NewSlice(elem)
+		}
+	}
+	return nil
+}
+
+// unalias replaces aliases with actual types in a type and its element types.
+func unalias(t types.Type) types.Type {
+	switch t := unaliasType(t).(type) {
+	case *types.Pointer:
+		return types.NewPointer(unalias(t.Elem()))
+	case *types.Slice:
+		return types.NewSlice(unalias(t.Elem()))
+	case *types.Array:
+		return types.NewArray(unalias(t.Elem()), t.Len())
+	case *types.Map:
+		return types.NewMap(unalias(t.Key()), unalias(t.Elem()))
+	case *types.Chan:
+		return types.NewChan(t.Dir(), unalias(t.Elem()))
+	default:
+		return t
+	}
+}
+
+// Represents a string by a value with a name, which is declared with effe.As, and a type.
+// Unnamed values are represented by types. Types are represented by keys from GetTypeKey.
+func GetValueKey(info *types.Info, name string, t ast.Expr) string {
+	if name == "" {
+		return GetTypeKey(info, t)
+	}
+	return name + " " + GetTypeKey(info, t)
+}
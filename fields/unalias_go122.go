@@ -0,0 +1,12 @@
+//go:build go1.22
+// +build go1.22
+
+package fields
+
+import "go/types"
+
+// unaliasType returns an actual type of an alias. Since Go 1.22 the type checker
+// can represent aliases with types.Alias.
+func unaliasType(t types.Type) types.Type {
+	return types.Unalias(t)
+}
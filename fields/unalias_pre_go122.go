@@ -0,0 +1,12 @@
+//go:build !go1.22
+// +build !go1.22
+
+package fields
+
+import "go/types"
+
+// unaliasType returns a type as is, because before Go 1.22 the type checker
+// replaces aliases with actual types.
+func unaliasType(t types.Type) types.Type {
+	return t
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"go/ast"
+	goTypes "go/types"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,6 +14,7 @@ import (
 type flowGen struct {
 	pkgFuncDecls map[string]*ast.FuncDecl
 	implFields   map[string]implFieldInfo
+	typesInfo    *goTypes.Info
 }
 
 func (f *flowGen) genImplFields(c types.Component) {
@@ -29,6 +31,9 @@ func (f *flowGen) genImplFields(c types.Component) {
 		if c.Failure != nil {
 			f.genImplField(c.Failure)
 		}
+		if c.Finally != nil {
+			f.genImplField(c.Finally)
+		}
 		for _, child := range c.Children {
 			f.genImplFields(child)
 		}
@@ -40,10 +45,38 @@ func (f *flowGen) genImplFields(c types.Component) {
 		for _, decisionCase := range c.Cases {
 			f.genImplFields(decisionCase)
 		}
+		if c.Default != nil {
+			f.genImplFields(c.Default)
+		}
 	case *types.CaseComponent:
 		for _, child := range c.Children {
 			f.genImplFields(child)
 		}
+	case *types.ParallelComponent:
+		for _, child := range c.Children {
+			f.genImplFields(child)
+		}
+	case *types.RetryComponent:
+		if c.RetryIf != nil {
+			f.genImplField(c.RetryIf)
+		}
+		f.genImplFields(c.Child)
+	case *types.TimeoutComponent:
+		for _, child := range c.Children {
+			f.genImplFields(child)
+		}
+	case *types.ForEachComponent:
+		for _, child := range c.Children {
+			f.genImplFields(child)
+		}
+	case *types.IfComponent:
+		f.genImplField(c.Predicate)
+		for _, child := range c.Then {
+			f.genImplFields(child)
+		}
+		for _, child := range c.Else {
+			f.genImplFields(child)
+		}
 	}
 }
 
@@ -57,8 +90,13 @@ func (f *flowGen) genImplField(simple *types.SimpleComponent) {
 		output:           simple.Output,
 		serviceFuncName:  simple.FuncName,
 		originalFuncName: simple.OriginalFuncName,
+		pkg:              simple.Package,
+		receiver:         simple.Receiver,
 		deps:             simple.Deps,
 	}
+	if simple.Compensate != nil {
+		f.genImplField(simple.Compensate)
+	}
 }
 
 func (f *flowGen) sortedImplFields() []implFieldInfo {
@@ -76,12 +114,12 @@ func (f flowGen) getSortedFlowDependecies() []*ast.Field {
 	depsSet := map[string]*ast.Field{}
 	for _, fieldInfo := range f.implFields {
 		for _, dep := range fieldInfo.deps.List {
-			strTypeName := fields.GetTypeStrName(dep.Type)
-			_, ok := depsSet[strTypeName]
+			typeKey := fields.GetTypeKey(f.typesInfo, dep.Type)
+			_, ok := depsSet[typeKey]
 			if ok {
 				continue
 			}
-			depsSet[strTypeName] = &ast.Field{
+			depsSet[typeKey] = &ast.Field{
 				Type: dep.Type,
 				Names: []*ast.Ident{
 					{
@@ -6,12 +6,15 @@ import (
 	"strings"
 
 	"github.com/GettEngineering/effe/fields"
+	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
 )
 
 type implFieldInfo struct {
 	serviceFuncName  *ast.Ident
 	originalFuncName *ast.Ident
+	pkg              *ast.Ident
+	receiver         ast.Expr
 	input            *ast.FieldList
 	output           *ast.FieldList
 	deps             *ast.FieldList
@@ -33,8 +36,11 @@ func (g Generator) genFlowFunc(funcName, interfaceName *ast.Ident, flowFunc *ast
 	return typeFunc, newFlowFunc(g.settings.LocalInterfaceVarname(), interfaceName, funcName, typeFunc.Name, flowFunc)
 }
 
-func (g Generator) genImplField(impleName *ast.Ident, field implFieldInfo, deps []*ast.Field) (*ast.Field, *ast.FuncDecl, *ast.KeyValueExpr) {
+func (g Generator) genImplField(impleName *ast.Ident, field implFieldInfo, deps []*ast.Field, typesInfo *types.Info) (*ast.Field, *ast.FuncDecl, *ast.KeyValueExpr) {
 	structFieldIdent := ast.NewIdent(field.originalFuncName.Name + g.settings.ImplFieldPostfix())
+	if field.pkg != nil || field.receiver != nil {
+		structFieldIdent = ast.NewIdent(strcase.ToLowerCamel(field.serviceFuncName.Name) + g.settings.ImplFieldPostfix())
+	}
 	structField := &ast.Field{
 		Names: []*ast.Ident{structFieldIdent},
 		Type: &ast.FuncType{
@@ -45,21 +51,36 @@ func (g Generator) genImplField(impleName *ast.Ident, field implFieldInfo, deps
 
 	depArgs := []ast.Expr{}
 	for _, dep := range field.deps.List {
-		flowDep := fields.FindFieldWithType(deps, dep.Type)
+		flowDep := fields.FindFieldWithTypeInfo(typesInfo, deps, dep.Type)
 		depArgs = append(depArgs, flowDep.Names[0])
 	}
 
-	assignExpr := &ast.KeyValueExpr{
-		Key: structFieldIdent,
-		Value: &ast.CallExpr{
+	var value ast.Expr
+	switch {
+	case field.receiver != nil:
+		// A method value of the receiver is used as the step function.
+		value = &ast.SelectorExpr{X: depArgs[0], Sel: field.originalFuncName}
+	case field.pkg != nil:
+		value = &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: field.pkg, Sel: field.originalFuncName},
+			Args: depArgs,
+		}
+	default:
+		value = &ast.CallExpr{
 			Fun:  field.originalFuncName,
 			Args: depArgs,
-		},
+		}
+	}
+	assignExpr := &ast.KeyValueExpr{
+		Key:   structFieldIdent,
+		Value: value,
 	}
 
 	callArgs := []ast.Expr{}
 	for _, input := range field.input.List {
-		callArgs = append(callArgs, input.Names[0])
+		for _, name := range input.Names {
+			callArgs = append(callArgs, name)
+		}
 	}
 
 	impleNameIdent := ast.NewIdent(strings.ToLower(string([]rune(impleName.Name)[0])))
@@ -121,7 +142,7 @@ func (g Generator) genImplementation(impleName, newImpleFuncName *ast.Ident, f *
 	assignExprs := []ast.Expr{}
 
 	for _, field := range f.sortedImplFields() {
-		strField, implFunc, assignExp := g.genImplField(impleName, field, allDeps)
+		strField, implFunc, assignExp := g.genImplField(impleName, field, allDeps, f.typesInfo)
 		assignExprs = append(assignExprs, assignExp)
 		structType.Fields.List = append(structType.Fields.List, strField)
 		funcDecls = append(funcDecls, implFunc)
@@ -159,11 +180,13 @@ func genInterface(interfaceName *ast.Ident, f *flowGen) *ast.TypeSpec {
 }
 
 func (g Generator) genFlow(flowFunc *ast.FuncDecl, buildFlowFuncCall *ast.CallExpr, f *flowGen, typesInfo *types.Info) (*flowGenRes, error) {
+	g.setLoaderTypesInfo(typesInfo)
 	flowComponents, failureComponent, err := g.loader.LoadFlow(buildFlowFuncCall.Args, f.pkgFuncDecls)
 	if err != nil {
 		return nil, err
 	}
 
+	g.setStrategyFlow(flowFunc)
 	fn, imports, err := g.strategy.BuildFlow(flowComponents, failureComponent, typesInfo)
 	if err != nil {
 		return nil, err
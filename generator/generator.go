@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"go/ast"
+	"go/token"
 	goTypes "go/types"
+	"strings"
 
 	"github.com/GettEngineering/effe/types"
 	"github.com/pkg/errors"
@@ -14,6 +16,9 @@ import (
 const (
 	// The function from Effe, which declares flow
 	BuildFLowExprType = "BuildFlow"
+
+	// DirectivePrefix is a prefix of comments of a flow function, which are passed to Strategy as directives.
+	DirectivePrefix = "//effe:"
 )
 
 // Generator loads dsl, generates code or diagrams.
@@ -22,6 +27,7 @@ type Generator struct {
 	strategy Strategy
 	loader   Loader
 	drawer   Drawer
+	exporter Exporter
 }
 
 // Loader executes parsers for components by type. Generator gets arguments from
@@ -31,6 +37,12 @@ type Loader interface {
 	LoadFlow([]ast.Expr, map[string]*ast.FuncDecl) ([]types.Component, types.Component, error)
 }
 
+// TypesInfoLoader is an optional interface of Loader. Generator passes information
+// about types of a package before loading flows of the package.
+type TypesInfoLoader interface {
+	SetTypesInfo(*goTypes.Info)
+}
+
 // Strategy generates the flow function.
 type Strategy interface {
 	// BuildFlow takes a list of components and a failure component and returns
@@ -38,6 +50,18 @@ type Strategy interface {
 	BuildFlow([]types.Component, types.Component, *goTypes.Info) (ast.Expr, []string, error)
 }
 
+// FlowNameStrategy is an optional interface of Strategy. Generator passes a name
+// of a flow before building the flow.
+type FlowNameStrategy interface {
+	SetFlowName(string)
+}
+
+// DirectivesStrategy is an optional interface of Strategy. Generator passes directives
+// of a flow, comments //effe:<directive>, before building the flow.
+type DirectivesStrategy interface {
+	SetFlowDirectives([]string)
+}
+
 // Drawe draws graphs for business flows.
 type Drawer interface {
 	// DrawBuild takes a list of compoments and a failure components, returns
@@ -45,6 +69,29 @@ type Drawer interface {
 	DrawFlow([]types.Component, types.Component) (string, error)
 }
 
+// Exporter converts business flows to a machine-readable description.
+type Exporter interface {
+	// ExportFlows takes a package path, a file set and flows of the package
+	// and returns a content of an output file
+	ExportFlows(string, *token.FileSet, []types.Flow) ([]byte, error)
+}
+
+// DiagramFormat is an optional interface for a Drawer, which draws diagrams
+// in a format other than plantuml. Generator uses it for writing files with diagrams.
+type DiagramFormat interface {
+	// Ext returns an extension of files with diagrams, for example mmd
+	Ext() string
+
+	// Wrap adds a header and a footer of a diagram to a graph of a flow
+	Wrap(flowName string, graph string) string
+}
+
+// NestedFlowsDrawer is an optional interface for a Drawer, which draws flows used
+// as steps by other flows inline. Generator passes all flows of a package before drawing.
+type NestedFlowsDrawer interface {
+	SetFlows([]types.Flow)
+}
+
 type Option func(g *Generator)
 
 // WithSetttings is used for overriding settings
@@ -62,6 +109,13 @@ func WithDrawer(d Drawer) Option {
 	}
 }
 
+// WithExporter is used for overriding an exporter
+func WithExporter(e Exporter) Option {
+	return func(g *Generator) {
+		g.exporter = e
+	}
+}
+
 // WithDrawer is used for overriding a loader
 func WithLoader(l Loader) Option {
 	return func(g *Generator) {
@@ -103,7 +157,7 @@ func (g *Generator) GenerateDiagram(ctx context.Context, wd string, env []string
 			continue
 		}
 
-		outputFlows, err := writeDiagrams(pkg, outputDir, res)
+		outputFlows, err := writeDiagrams(pkg, outputDir, res, g.diagramFormat())
 		if err != nil {
 			genRes.Errs = append(genRes.Errs, errs...)
 			generated = append(generated, genRes)
@@ -120,36 +174,84 @@ func (g *Generator) GenerateDiagram(ctx context.Context, wd string, env []string
 	return generated, nil
 }
 
+// diagramFormat returns a format of diagrams from the drawer, default is plantuml.
+func (g *Generator) diagramFormat() DiagramFormat {
+	if format, ok := g.drawer.(DiagramFormat); ok {
+		return format
+	}
+	return plantumlFormat{}
+}
+
 type drawFlowRes struct {
 	name  string
 	graph string
 }
 
 func (g *Generator) generateDiagramForPkg(pkg *packages.Package) ([]drawFlowRes, []error) {
-	pkgFuncDecls, flowDecls := g.loadFuncsAndFlows(pkg)
+	flows, errs := g.loadFlows(pkg)
+	if flows == nil {
+		return nil, errs
+	}
+
+	if nestedFlowsDrawer, ok := g.drawer.(NestedFlowsDrawer); ok {
+		nestedFlowsDrawer.SetFlows(flows)
+	}
+
+	res := make([]drawFlowRes, 0)
+	for _, flow := range flows {
+		flowGraph, err := g.drawer.DrawFlow(flow.Components, flow.Failure)
+		if err != nil {
+			errs = append(errs, errors.Errorf("can't load flow: %s, error: %s", flow.Name, err))
+			continue
+		}
+		res = append(res, drawFlowRes{
+			name:  flow.Name.Name,
+			graph: flowGraph,
+		})
+	}
+
+	return res, errs
+}
+
+func (g *Generator) setLoaderTypesInfo(info *goTypes.Info) {
+	if l, ok := g.loader.(TypesInfoLoader); ok {
+		l.SetTypesInfo(info)
+	}
+}
+
+func (g *Generator) setStrategyFlow(flowFunc *ast.FuncDecl) {
+	if s, ok := g.strategy.(FlowNameStrategy); ok {
+		s.SetFlowName(flowFunc.Name.Name)
+	}
+	if s, ok := g.strategy.(DirectivesStrategy); ok {
+		s.SetFlowDirectives(flowDirectives(flowFunc))
+	}
+}
+
+// loadFlows loads flows of a package in order of dependencies between flows.
+// A flow, which is used by another flow, is loaded as a step without dependencies.
+func (g *Generator) loadFlows(pkg *packages.Package) ([]types.Flow, []error) {
+	pkgFuncDecls, flowDecls, typesInfo := g.loadFuncsAndFlows(pkg)
 	analyzer := newAnayzer(flowDecls)
 	sortedFlowDecls, errs := analyzer.sortFlowDeclsByDependecies()
 	if len(errs) > 0 {
 		return nil, errs
 	}
 
-	flows := make([]drawFlowRes, 0)
+	flows := make([]types.Flow, 0)
 
+	g.setLoaderTypesInfo(typesInfo)
 	for _, flowDecl := range sortedFlowDecls {
 		flowComponents, failureComponent, err := g.loader.LoadFlow(flowDecl.buildFlowFuncCall.Args, pkgFuncDecls)
 		if err != nil {
 			errs = append(errs, errors.Errorf("can't load flow: %s, error: %s", flowDecl.flowFunc.Name, err))
 			continue
 		}
-		var flowGraph string
-		flowGraph, err = g.drawer.DrawFlow(flowComponents, failureComponent)
-		if err != nil {
-			errs = append(errs, errors.Errorf("can't load flow: %s, error: %s", flowDecl.flowFunc.Name, err))
-			continue
-		}
-		flows = append(flows, drawFlowRes{
-			name:  flowDecl.flowFunc.Name.Name,
-			graph: flowGraph,
+		flows = append(flows, types.Flow{
+			Name:       flowDecl.flowFunc.Name,
+			Components: flowComponents,
+			Failure:    failureComponent,
+			Directives: flowDirectives(flowDecl.flowFunc),
 		})
 		pkgFuncDecls[flowDecl.flowFunc.Name.Name] = generateEmptyFlowFuncAsStepDeclaration(flowDecl.flowFunc)
 	}
@@ -157,6 +259,40 @@ func (g *Generator) generateDiagramForPkg(pkg *packages.Package) ([]drawFlowRes,
 	return flows, errs
 }
 
+// GenerateJSON writes machine-readable descriptions of business flows of a package to a file effe_flows.json.
+func (g *Generator) GenerateJSON(ctx context.Context, wd string, env []string, patterns []string) ([]types.GenerateResult, []error) {
+	pkgs, errs := load(ctx, wd, env, patterns)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	generated := make([]types.GenerateResult, 0)
+	for _, pkg := range pkgs {
+		genRes := types.GenerateResult{
+			PkgPath: pkg.PkgPath,
+		}
+		flows, errs := g.loadFlows(pkg)
+		if len(errs) > 0 {
+			genRes.Errs = append(genRes.Errs, errs...)
+			generated = append(generated, genRes)
+			continue
+		}
+
+		content, err := g.exporter.ExportFlows(pkg.PkgPath, pkg.Fset, flows)
+		if err != nil {
+			genRes.Errs = append(genRes.Errs, err)
+			generated = append(generated, genRes)
+			continue
+		}
+
+		genRes.OutputPath, err = writeExportedFlows(pkg, content)
+		if err != nil {
+			genRes.Errs = append(genRes.Errs, err)
+		}
+		generated = append(generated, genRes)
+	}
+	return generated, nil
+}
+
 func generateEmptyFlowFuncAsStepDeclaration(flowFuncDecl *ast.FuncDecl) *ast.FuncDecl {
 	return &ast.FuncDecl{
 		Name: flowFuncDecl.Name,
@@ -227,6 +363,20 @@ func (f flowDecl) FlowName() string {
 	return f.flowFunc.Name.Name
 }
 
+// flowDirectives returns directives from comments //effe:<directive> of a function which declares a flow.
+func flowDirectives(flowFunc *ast.FuncDecl) []string {
+	if flowFunc.Doc == nil {
+		return nil
+	}
+	var directives []string
+	for _, comment := range flowFunc.Doc.List {
+		if strings.HasPrefix(comment.Text, DirectivePrefix) {
+			directives = append(directives, strings.TrimSpace(strings.TrimPrefix(comment.Text, DirectivePrefix)))
+		}
+	}
+	return directives
+}
+
 type pkgGen struct {
 	flowFuncDecls           []*ast.FuncDecl
 	depInitializerFuncDecls []*ast.FuncDecl
@@ -235,9 +385,17 @@ type pkgGen struct {
 	imports                 []string
 }
 
-func (g *Generator) loadFuncsAndFlows(pkg *packages.Package) (map[string]*ast.FuncDecl, []flowDecl) {
+// loadFuncsAndFlows returns declarations of steps, declarations of flows and types info for the declarations.
+// The types info is types info of the package extended with types of declarations which are built
+// for steps, for example for steps from imported packages.
+func (g *Generator) loadFuncsAndFlows(pkg *packages.Package) (map[string]*ast.FuncDecl, []flowDecl, *goTypes.Info) {
 	pkgFuncDecls := make(map[string]*ast.FuncDecl)
 	flowDecls := []flowDecl{}
+	stepsInfo := &goTypes.Info{
+		Types: make(map[ast.Expr]goTypes.TypeAndValue),
+		Uses:  make(map[*ast.Ident]goTypes.Object),
+	}
+	b := newSignatureBuilder(pkg.Types, stepsInfo)
 
 	for _, f := range pkg.Syntax {
 		for _, decl := range f.Decls {
@@ -248,7 +406,14 @@ func (g *Generator) loadFuncsAndFlows(pkg *packages.Package) (map[string]*ast.Fu
 			if fn.Body == nil {
 				continue
 			}
-			pkgFuncDecls[fn.Name.String()] = fn
+			pkgFuncDecls[fn.Name.String()] = withStepFuncType(pkg, b, fn)
+			if fn.Recv != nil {
+				if typeIdent := receiverTypeIdent(fn); typeIdent != nil {
+					for name, stepDecl := range methodStepDecls(fn, typeIdent, fn.Type) {
+						pkgFuncDecls[name] = stepDecl
+					}
+				}
+			}
 			buildFlowFuncCall := findExprInBody(fn, pkg, BuildFLowExprType)
 			if buildFlowFuncCall == nil {
 				continue
@@ -261,11 +426,17 @@ func (g *Generator) loadFuncsAndFlows(pkg *packages.Package) (map[string]*ast.Fu
 		}
 	}
 
-	return pkgFuncDecls, flowDecls
+	for name, decl := range loadImportedStepDecls(pkg, usedSelectors(flowDecls), stepsInfo) {
+		if _, ok := pkgFuncDecls[name]; !ok {
+			pkgFuncDecls[name] = decl
+		}
+	}
+
+	return pkgFuncDecls, flowDecls, mergeTypesInfo(pkg.TypesInfo, stepsInfo)
 }
 
 func (g *Generator) generateForPackage(pkg *packages.Package) (*pkgGen, []error) {
-	pkgFuncDecls, flowDecls := g.loadFuncsAndFlows(pkg)
+	pkgFuncDecls, flowDecls, typesInfo := g.loadFuncsAndFlows(pkg)
 	analyzer := newAnayzer(flowDecls)
 	sortedFlowDecls, errs := analyzer.sortFlowDeclsByDependecies()
 	if len(errs) > 0 {
@@ -278,9 +449,10 @@ func (g *Generator) generateForPackage(pkg *packages.Package) (*pkgGen, []error)
 		f := &flowGen{
 			pkgFuncDecls: pkgFuncDecls,
 			implFields:   make(map[string]implFieldInfo),
+			typesInfo:    typesInfo,
 		}
 
-		res, err := g.genFlow(flowDecl.flowFunc, flowDecl.buildFlowFuncCall, f, pkg.TypesInfo)
+		res, err := g.genFlow(flowDecl.flowFunc, flowDecl.buildFlowFuncCall, f, typesInfo)
 		if err != nil {
 			loadErr, ok := err.(*types.LoadError)
 			if ok {
@@ -295,10 +467,18 @@ func (g *Generator) generateForPackage(pkg *packages.Package) (*pkgGen, []error)
 		//Import types, which are used in flow
 		for _, fieldInfo := range f.implFields {
 			if fieldInfo.input != nil {
-				mergeImportSets(importSet, getExportedType(pkg, fieldInfo.input))
+				mergeImportSets(importSet, getExportedType(typesInfo, fieldInfo.input))
 			}
 			if fieldInfo.output != nil {
-				mergeImportSets(importSet, getExportedType(pkg, fieldInfo.output))
+				mergeImportSets(importSet, getExportedType(typesInfo, fieldInfo.output))
+			}
+			if fieldInfo.deps != nil {
+				mergeImportSets(importSet, getImportedTypes(typesInfo, fieldInfo.deps))
+			}
+			if fieldInfo.pkg != nil {
+				if pkgName, ok := typesInfo.ObjectOf(fieldInfo.pkg).(*goTypes.PkgName); ok {
+					importSet[pkgName.Imported().Path()] = struct{}{}
+				}
 			}
 		}
 
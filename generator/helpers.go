@@ -9,19 +9,34 @@ import (
 	"golang.org/x/tools/go/packages"
 )
 
-func getExportedType(pkg *packages.Package, fieldSet *ast.FieldList) map[string]struct{} {
+func getExportedType(info *types.Info, fieldSet *ast.FieldList) map[string]struct{} {
 	importSet := make(map[string]struct{})
 	for _, input := range fieldSet.List {
-		paramObj := qualifiedIdentObject(pkg.TypesInfo, input.Type)
-		if paramObj == nil {
-			continue
+		paramObj := qualifiedIdentObject(info, input.Type)
+		if paramObj != nil && paramObj.Exported() {
+			importSet[paramObj.Pkg().Path()] = struct{}{}
 		}
-		if paramObj.Exported() {
-			_, ok := importSet[paramObj.Pkg().Path()]
+	}
+	mergeImportSets(importSet, getImportedTypes(info, fieldSet))
+	return importSet
+}
+
+// getImportedTypes returns paths of packages which declare types qualified by package names.
+// Types from other packages can be nested, for example *http.Request or []http.Header.
+func getImportedTypes(info *types.Info, fieldSet *ast.FieldList) map[string]struct{} {
+	importSet := make(map[string]struct{})
+	for _, input := range fieldSet.List {
+		ast.Inspect(input.Type, func(node ast.Node) bool {
+			sel, ok := node.(*ast.SelectorExpr)
 			if !ok {
-				importSet[paramObj.Pkg().Path()] = struct{}{}
+				return true
 			}
-		}
+			obj := qualifiedIdentObject(info, sel)
+			if obj != nil && obj.Pkg() != nil && obj.Exported() {
+				importSet[obj.Pkg().Path()] = struct{}{}
+			}
+			return false
+		})
 	}
 	return importSet
 }
@@ -0,0 +1,312 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// usedSelectors returns selectors which are used in flows, for example payments.ChargeStep.
+func usedSelectors(flowDecls []flowDecl) map[string]struct{} {
+	selectors := make(map[string]struct{})
+	for _, flowDecl := range flowDecls {
+		ast.Inspect(flowDecl.buildFlowFuncCall, func(node ast.Node) bool {
+			if sel, ok := node.(*ast.SelectorExpr); ok {
+				selectors[types.ExprString(sel)] = struct{}{}
+			}
+			return true
+		})
+	}
+	return selectors
+}
+
+// loadImportedStepDecls returns declarations of steps from packages imported in a package.
+// A key of a declaration is an expression which is used in DSL, for example payments.ChargeStep.
+// Only steps with keys from selectors are loaded.
+// Types in the declarations are qualified by names of imported packages, so the declarations
+// can be used in generated code as declarations from the package. Types of expressions
+// in the declarations are recorded in info.
+func loadImportedStepDecls(pkg *packages.Package, selectors map[string]struct{}, info *types.Info) map[string]*ast.FuncDecl {
+	decls := make(map[string]*ast.FuncDecl)
+	if len(selectors) == 0 {
+		return decls
+	}
+	for _, f := range pkg.Syntax {
+		for _, importSpec := range f.Imports {
+			path, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil || isEffeImport(path) {
+				continue
+			}
+			importedPkg, ok := pkg.Imports[path]
+			if !ok || importedPkg.Types == nil {
+				continue
+			}
+
+			importName := importedPkg.Name
+			if importSpec.Name != nil {
+				importName = importSpec.Name.Name
+			}
+			if importName == "_" || importName == "." {
+				continue
+			}
+
+			pkgName := types.NewPkgName(token.NoPos, pkg.Types, importName, importedPkg.Types)
+			b := newSignatureBuilder(pkg.Types, info)
+			b.pkgNames[importedPkg.Types] = pkgName
+			q := &qualifier{
+				info:        info,
+				importedPkg: importedPkg,
+				pkgName:     pkgName,
+				signatures:  b,
+			}
+			for _, importedFile := range importedPkg.Syntax {
+				for _, decl := range importedFile.Decls {
+					fn, ok := decl.(*ast.FuncDecl)
+					if !ok || !fn.Name.IsExported() {
+						continue
+					}
+					if fn.Recv != nil {
+						if !isMethodSelected(selectors, importName, fn) {
+							continue
+						}
+						for name, stepDecl := range q.methodStepDecls(fn) {
+							decls[name] = stepDecl
+						}
+						continue
+					}
+					name := importName + "." + fn.Name.Name
+					if _, ok := selectors[name]; !ok {
+						continue
+					}
+					stepDecl, ok := q.stepDecl(fn)
+					if !ok {
+						continue
+					}
+					decls[name] = stepDecl
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// isMethodSelected checks that a method of a type from an imported package is used
+// with one of method expressions: (*pkg.T).Method or pkg.T.Method.
+func isMethodSelected(selectors map[string]struct{}, importName string, fn *ast.FuncDecl) bool {
+	typeIdent := receiverTypeIdent(fn)
+	if typeIdent == nil {
+		return false
+	}
+	typeName := importName + "." + typeIdent.Name
+	for _, name := range []string{"(*" + typeName + ")." + fn.Name.Name, typeName + "." + fn.Name.Name} {
+		if _, ok := selectors[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeTypesInfo returns types info of a package with types of expressions from other types info.
+// Types info of the package isn't changed, because it's built by the type checker.
+func mergeTypesInfo(pkgInfo, info *types.Info) *types.Info {
+	if len(info.Types) == 0 && len(info.Uses) == 0 {
+		return pkgInfo
+	}
+	merged := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue, len(pkgInfo.Types)+len(info.Types)),
+		Defs:       pkgInfo.Defs,
+		Uses:       make(map[*ast.Ident]types.Object, len(pkgInfo.Uses)+len(info.Uses)),
+		Implicits:  pkgInfo.Implicits,
+		Selections: pkgInfo.Selections,
+		Scopes:     pkgInfo.Scopes,
+	}
+	for _, m := range []*types.Info{pkgInfo, info} {
+		for expr, t := range m.Types {
+			merged.Types[expr] = t
+		}
+		for ident, obj := range m.Uses {
+			merged.Uses[ident] = obj
+		}
+	}
+	return merged
+}
+
+// qualifier copies type expressions from an imported package and qualifies
+// types declared in the imported package by a name of the package.
+// Types of copied expressions are recorded in info.
+type qualifier struct {
+	info        *types.Info
+	importedPkg *packages.Package
+	pkgName     *types.PkgName
+	// signatures builds function types of steps which return named function types
+	signatures *signatureBuilder
+}
+
+// stepDecl builds a declaration of a step which returns a function without a body.
+// If the function doesn't look like a step or uses unexported types, stepDecl returns false.
+func (q *qualifier) stepDecl(fn *ast.FuncDecl) (*ast.FuncDecl, bool) {
+	if fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+		return nil, false
+	}
+	deps, ok := q.fieldList(fn.Type.Params)
+	if !ok {
+		return nil, false
+	}
+
+	var stepType ast.Expr
+	if stepFuncType, isFuncType := fn.Type.Results.List[0].Type.(*ast.FuncType); isFuncType {
+		stepType, ok = q.expr(stepFuncType)
+	} else {
+		stepType, ok = q.signatures.stepFuncType(q.importedPkg.TypesInfo.TypeOf(fn.Type.Results.List[0].Type))
+	}
+	if !ok {
+		return nil, false
+	}
+
+	return &ast.FuncDecl{
+		Name: ast.NewIdent(fn.Name.Name),
+		Type: &ast.FuncType{
+			Params: deps,
+			Results: &ast.FieldList{
+				List: []*ast.Field{{Type: stepType}},
+			},
+		},
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.ReturnStmt{
+					Results: []ast.Expr{
+						&ast.FuncLit{
+							Type: stepType.(*ast.FuncType),
+							Body: &ast.BlockStmt{},
+						},
+					},
+				},
+			},
+		},
+	}, true
+}
+
+// methodStepDecls builds declarations of steps for a method of an exported type.
+func (q *qualifier) methodStepDecls(fn *ast.FuncDecl) map[string]*ast.FuncDecl {
+	typeIdent := receiverTypeIdent(fn)
+	if typeIdent == nil || !typeIdent.IsExported() {
+		return nil
+	}
+	typeName, ok := q.expr(typeIdent)
+	if !ok {
+		return nil
+	}
+	funcType, ok := q.expr(fn.Type)
+	if !ok {
+		return nil
+	}
+	return methodStepDecls(fn, typeName, funcType.(*ast.FuncType))
+}
+
+func (q *qualifier) fieldList(list *ast.FieldList) (*ast.FieldList, bool) {
+	if list == nil {
+		return nil, true
+	}
+
+	result := &ast.FieldList{}
+	for _, field := range list.List {
+		t, ok := q.expr(field.Type)
+		if !ok {
+			return nil, false
+		}
+		names := make([]*ast.Ident, len(field.Names))
+		for i, name := range field.Names {
+			names[i] = ast.NewIdent(name.Name)
+		}
+		result.List = append(result.List, &ast.Field{Names: names, Type: t})
+	}
+	return result, true
+}
+
+func (q *qualifier) expr(expr ast.Expr) (ast.Expr, bool) {
+	var (
+		result ast.Expr
+		ok     = true
+	)
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		result, ok = q.ident(expr)
+	case *ast.SelectorExpr:
+		pkgIdent, isIdent := expr.X.(*ast.Ident)
+		if !isIdent {
+			return nil, false
+		}
+		x := ast.NewIdent(pkgIdent.Name)
+		sel := ast.NewIdent(expr.Sel.Name)
+		q.info.Uses[x] = q.importedPkg.TypesInfo.ObjectOf(pkgIdent)
+		q.info.Uses[sel] = q.importedPkg.TypesInfo.ObjectOf(expr.Sel)
+		result = &ast.SelectorExpr{X: x, Sel: sel}
+	case *ast.StarExpr:
+		var x ast.Expr
+		x, ok = q.expr(expr.X)
+		result = &ast.StarExpr{X: x}
+	case *ast.ArrayType:
+		var elt ast.Expr
+		elt, ok = q.expr(expr.Elt)
+		result = &ast.ArrayType{Len: expr.Len, Elt: elt}
+	case *ast.MapType:
+		key, keyOk := q.expr(expr.Key)
+		value, valueOk := q.expr(expr.Value)
+		ok = keyOk && valueOk
+		result = &ast.MapType{Key: key, Value: value}
+	case *ast.ChanType:
+		var value ast.Expr
+		value, ok = q.expr(expr.Value)
+		result = &ast.ChanType{Dir: expr.Dir, Value: value}
+	case *ast.Ellipsis:
+		var elt ast.Expr
+		elt, ok = q.expr(expr.Elt)
+		result = &ast.Ellipsis{Elt: elt}
+	case *ast.FuncType:
+		params, paramsOk := q.fieldList(expr.Params)
+		results, resultsOk := q.fieldList(expr.Results)
+		ok = paramsOk && resultsOk
+		result = &ast.FuncType{Params: params, Results: results}
+	case *ast.InterfaceType:
+		if len(expr.Methods.List) > 0 {
+			return nil, false
+		}
+		result = &ast.InterfaceType{Methods: &ast.FieldList{}}
+	default:
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	if t := q.importedPkg.TypesInfo.TypeOf(expr); t != nil {
+		q.info.Types[result] = types.TypeAndValue{Type: t}
+	}
+	return result, true
+}
+
+// ident qualifies an identifier of a type declared in the imported package.
+// Identifiers of predeclared types are copied as is.
+func (q *qualifier) ident(ident *ast.Ident) (ast.Expr, bool) {
+	obj := q.importedPkg.TypesInfo.ObjectOf(ident)
+	if obj == nil {
+		return nil, false
+	}
+	if obj.Pkg() == nil {
+		result := ast.NewIdent(ident.Name)
+		q.info.Uses[result] = obj
+		return result, true
+	}
+	if !obj.Exported() {
+		return nil, false
+	}
+
+	x := ast.NewIdent(q.pkgName.Name())
+	sel := ast.NewIdent(ident.Name)
+	q.info.Uses[x] = q.pkgName
+	q.info.Uses[sel] = obj
+	return &ast.SelectorExpr{X: x, Sel: sel}, true
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"go/ast"
+	"go/types"
+
+	"github.com/iancoleman/strcase"
+)
+
+// methodStepDecls returns declarations of steps for a method. The steps can be declared
+// with method expressions: (*T).Method for all methods and T.Method for methods with a value receiver.
+// A receiver of a declaration is the only dependency of the step and a result of the declaration
+// is a signature of the method. A type name is a qualified name of the receiver type.
+func methodStepDecls(fn *ast.FuncDecl, typeName ast.Expr, funcType *ast.FuncType) map[string]*ast.FuncDecl {
+	decls := make(map[string]*ast.FuncDecl)
+	ptrType := &ast.StarExpr{X: typeName}
+	decls["(*"+types.ExprString(typeName)+")."+fn.Name.Name] = newMethodStepDecl(fn, ptrType, funcType)
+	if _, isPtr := fn.Recv.List[0].Type.(*ast.StarExpr); !isPtr {
+		decls[types.ExprString(typeName)+"."+fn.Name.Name] = newMethodStepDecl(fn, typeName, funcType)
+	}
+	return decls
+}
+
+func newMethodStepDecl(fn *ast.FuncDecl, receiverType ast.Expr, funcType *ast.FuncType) *ast.FuncDecl {
+	receiverName := ast.NewIdent(strcase.ToLowerCamel(receiverTypeName(receiverType)))
+	return &ast.FuncDecl{
+		Recv: fn.Recv,
+		Name: fn.Name,
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{
+				List: []*ast.Field{{Names: []*ast.Ident{receiverName}, Type: receiverType}},
+			},
+			Results: &ast.FieldList{
+				List: []*ast.Field{{Type: funcType}},
+			},
+		},
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.ReturnStmt{
+					Results: []ast.Expr{
+						&ast.FuncLit{
+							Type: funcType,
+							Body: &ast.BlockStmt{},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// receiverTypeName returns a type name of a receiver without a package name.
+func receiverTypeName(t ast.Expr) string {
+	switch t := t.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	default:
+		return types.ExprString(t)
+	}
+}
+
+// receiverTypeIdent returns an identifier of a receiver type of a method declaration.
+// Methods of generic types are not supported, so receiverTypeIdent returns nil for them.
+func receiverTypeIdent(fn *ast.FuncDecl) *ast.Ident {
+	t := fn.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	ident, _ := t.(*ast.Ident)
+	return ident
+}
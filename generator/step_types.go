@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// withStepFuncType returns a declaration of a step which returns a named function type
+// with a function type as a result, for example
+//
+//	type Handler func(ctx context.Context, req *Request) (*Response, error)
+//
+//	func NewHandler(client *http.Client) Handler
+//
+// The function type is built from a signature of the result type, so the type can be declared
+// in another package, for example http.HandlerFunc.
+// A body of the step is not changed, so the step can contain any setup code.
+func withStepFuncType(pkg *packages.Package, b *signatureBuilder, fn *ast.FuncDecl) *ast.FuncDecl {
+	if fn.Recv != nil || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+		return fn
+	}
+	resultType := fn.Type.Results.List[0].Type
+	if _, ok := resultType.(*ast.FuncType); ok {
+		return fn
+	}
+
+	funcType, ok := b.stepFuncType(pkg.TypesInfo.TypeOf(resultType))
+	if !ok {
+		return fn
+	}
+
+	stepDecl := *fn
+	stepDecl.Type = &ast.FuncType{
+		Func:   fn.Type.Func,
+		Params: fn.Type.Params,
+		Results: &ast.FieldList{
+			List: []*ast.Field{{Type: funcType}},
+		},
+	}
+	return &stepDecl
+}
+
+// signatureBuilder builds type expressions for types from the type checker.
+// Types declared in other packages are qualified by names of the packages.
+// Types of built expressions are recorded in info.
+type signatureBuilder struct {
+	pkg      *types.Package
+	info     *types.Info
+	pkgNames map[*types.Package]*types.PkgName
+}
+
+func newSignatureBuilder(pkg *types.Package, info *types.Info) *signatureBuilder {
+	return &signatureBuilder{
+		pkg:      pkg,
+		info:     info,
+		pkgNames: make(map[*types.Package]*types.PkgName),
+	}
+}
+
+// stepFuncType returns a function type for a type with an underlying signature. Names of parameters are
+// taken from the signature, parameters without names are named by positions, because they are passed
+// in generated code. If the type uses unexported types of other packages, stepFuncType returns false.
+func (b *signatureBuilder) stepFuncType(t types.Type) (*ast.FuncType, bool) {
+	if t == nil {
+		return nil, false
+	}
+	sig, ok := t.Underlying().(*types.Signature)
+	if !ok || sig.Recv() != nil {
+		return nil, false
+	}
+	funcType, ok := b.expr(sig)
+	if !ok {
+		return nil, false
+	}
+	result := funcType.(*ast.FuncType)
+	for index, param := range result.Params.List {
+		if len(param.Names) == 0 || param.Names[0].Name == "_" {
+			param.Names = []*ast.Ident{ast.NewIdent("arg" + strconv.Itoa(index+1))}
+		}
+	}
+	return result, true
+}
+
+func (b *signatureBuilder) tuple(tuple *types.Tuple, variadic bool) (*ast.FieldList, bool) {
+	list := &ast.FieldList{}
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		t := v.Type()
+		var (
+			expr ast.Expr
+			ok   bool
+		)
+		if variadic && i == tuple.Len()-1 {
+			var elt ast.Expr
+			elt, ok = b.expr(t.(*types.Slice).Elem())
+			expr = &ast.Ellipsis{Elt: elt}
+		} else {
+			expr, ok = b.expr(t)
+		}
+		if !ok {
+			return nil, false
+		}
+		field := &ast.Field{Type: expr}
+		if v.Name() != "" {
+			field.Names = []*ast.Ident{ast.NewIdent(v.Name())}
+		}
+		list.List = append(list.List, field)
+	}
+	return list, true
+}
+
+//nolint:gocyclo
+func (b *signatureBuilder) expr(t types.Type) (ast.Expr, bool) {
+	var (
+		result ast.Expr
+		ok     = true
+	)
+	switch typ := t.(type) {
+	case *types.Basic:
+		result = b.ident(typ.Name(), types.Universe.Lookup(typ.Name()))
+	case *types.Pointer:
+		var x ast.Expr
+		x, ok = b.expr(typ.Elem())
+		result = &ast.StarExpr{X: x}
+	case *types.Slice:
+		var elt ast.Expr
+		elt, ok = b.expr(typ.Elem())
+		result = &ast.ArrayType{Elt: elt}
+	case *types.Array:
+		var elt ast.Expr
+		elt, ok = b.expr(typ.Elem())
+		result = &ast.ArrayType{
+			Len: &ast.BasicLit{Kind: token.INT, Value: strconv.FormatInt(typ.Len(), 10)},
+			Elt: elt,
+		}
+	case *types.Map:
+		key, keyOk := b.expr(typ.Key())
+		value, valueOk := b.expr(typ.Elem())
+		ok = keyOk && valueOk
+		result = &ast.MapType{Key: key, Value: value}
+	case *types.Chan:
+		var value ast.Expr
+		value, ok = b.expr(typ.Elem())
+		dir := ast.SEND | ast.RECV
+		switch typ.Dir() {
+		case types.SendOnly:
+			dir = ast.SEND
+		case types.RecvOnly:
+			dir = ast.RECV
+		}
+		result = &ast.ChanType{Dir: dir, Value: value}
+	case *types.Signature:
+		params, paramsOk := b.tuple(typ.Params(), typ.Variadic())
+		results, resultsOk := b.tuple(typ.Results(), false)
+		ok = paramsOk && resultsOk
+		result = &ast.FuncType{Params: params, Results: results}
+	case *types.Interface:
+		if typ.NumMethods() > 0 {
+			return nil, false
+		}
+		result = &ast.InterfaceType{Methods: &ast.FieldList{}}
+	case interface{ Obj() *types.TypeName }:
+		result, ok = b.typeName(typ.Obj(), t)
+	default:
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	b.info.Types[result] = types.TypeAndValue{Type: t}
+	return result, true
+}
+
+// typeName builds an expression for a named type or an alias.
+// Instances of generic types aren't supported, because their type arguments
+// can't be found without API of Go 1.18.
+func (b *signatureBuilder) typeName(obj *types.TypeName, t types.Type) (ast.Expr, bool) {
+	if strings.HasSuffix(types.TypeString(t, nil), "]") {
+		return nil, false
+	}
+	if obj.Pkg() == nil {
+		return b.ident(obj.Name(), obj), true
+	}
+	if obj.Pkg() == b.pkg {
+		return b.ident(obj.Name(), obj), true
+	}
+	if !obj.Exported() {
+		return nil, false
+	}
+
+	pkgName, ok := b.pkgNames[obj.Pkg()]
+	if !ok {
+		pkgName = types.NewPkgName(token.NoPos, b.pkg, obj.Pkg().Name(), obj.Pkg())
+		b.pkgNames[obj.Pkg()] = pkgName
+	}
+	return &ast.SelectorExpr{
+		X:   b.ident(pkgName.Name(), pkgName),
+		Sel: b.ident(obj.Name(), obj),
+	}, true
+}
+
+func (b *signatureBuilder) ident(name string, obj types.Object) *ast.Ident {
+	ident := ast.NewIdent(name)
+	if obj != nil {
+		b.info.Uses[ident] = obj
+	}
+	return ident
+}
@@ -59,7 +59,7 @@ func (w *writer) reset() {
 	w.buf.Reset()
 }
 
-func writeDiagrams(pkg *packages.Package, outputDir string, res []drawFlowRes) ([]string, error) {
+func writeDiagrams(pkg *packages.Package, outputDir string, res []drawFlowRes, format DiagramFormat) ([]string, error) {
 	pkgDir, err := detectOutputDir(pkg.GoFiles)
 	if err != nil {
 		return nil, err
@@ -71,24 +71,47 @@ func writeDiagrams(pkg *packages.Package, outputDir string, res []drawFlowRes) (
 	}
 
 	outputFiles := make([]string, len(res))
-	buffer := new(bytes.Buffer)
 	for index, flowRes := range res {
-		outputName := filepath.Join(pkgDir, outputDir, fmt.Sprintf("%s.plantuml", flowRes.name))
-		buffer.WriteString("@startuml\n")
-		buffer.WriteString(fmt.Sprintf("right footer - %s\n", flowRes.name))
-		buffer.WriteString("scale 1.2\n\nskinparam monochrome true\nskinparam SequenceBoxBackgroundColor #FAFAFA\nskinparam SequenceBoxBorderColor #F0F0F0\nhide footbox\n")
-		buffer.WriteString(flowRes.graph)
-		buffer.WriteString("\n@enduml")
-		err = ioutil.WriteFile(outputName, buffer.Bytes(), 0600)
+		outputName := filepath.Join(pkgDir, outputDir, fmt.Sprintf("%s.%s", flowRes.name, format.Ext()))
+		err = ioutil.WriteFile(outputName, []byte(format.Wrap(flowRes.name, flowRes.graph)), 0600)
 		if err != nil {
 			return nil, err
 		}
-		buffer.Reset()
 		outputFiles[index] = outputName
 	}
 	return outputFiles, nil
 }
 
+func writeExportedFlows(pkg *packages.Package, content []byte) (string, error) {
+	outDir, err := detectOutputDir(pkg.GoFiles)
+	if err != nil {
+		return "", err
+	}
+	outputName := filepath.Join(outDir, "effe_flows.json")
+	err = ioutil.WriteFile(outputName, append(content, '\n'), 0600)
+	if err != nil {
+		return "", err
+	}
+	return outputName, nil
+}
+
+// plantumlFormat is a default format of diagrams.
+type plantumlFormat struct{}
+
+func (plantumlFormat) Ext() string {
+	return "plantuml"
+}
+
+func (plantumlFormat) Wrap(flowName string, graph string) string {
+	buffer := new(bytes.Buffer)
+	buffer.WriteString("@startuml\n")
+	buffer.WriteString(fmt.Sprintf("right footer - %s\n", flowName))
+	buffer.WriteString("scale 1.2\n\nskinparam monochrome true\nskinparam SequenceBoxBackgroundColor #FAFAFA\nskinparam SequenceBoxBorderColor #F0F0F0\nhide footbox\n")
+	buffer.WriteString(graph)
+	buffer.WriteString("\n@enduml")
+	return buffer.String()
+}
+
 func writeGeneratedCode(pkg *packages.Package, p *pkgGen) (string, error) {
 	w := &writer{}
 	outDir, err := detectOutputDir(pkg.GoFiles)
@@ -4,20 +4,38 @@ import (
 	"go/ast"
 
 	types "github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
 )
 
-// LoadCaseComponent converts an expression declared with effe.Case to a component with type types.CaseComponent
+// LoadCaseComponent converts an expression declared with effe.Case to a component with type types.CaseComponent.
+// Leading arguments which are not calls of DSL functions are keys of the case. If a flow loader doesn't
+// implement DSLCallChecker, only the first argument is a key.
 func LoadCaseComponent(
 	caseCallExpr *ast.CallExpr,
 	f FlowLoader,
 ) (types.Component, error) {
-	caseComponents, err := NewComponentsFromArgs(caseCallExpr.Args[1:], f)
+	keysCount := 1
+	if checker, ok := f.(DSLCallChecker); ok {
+		keysCount = 0
+		for keysCount < len(caseCallExpr.Args) && !checker.IsDSLCall(caseCallExpr.Args[keysCount]) {
+			keysCount++
+		}
+	}
+	if keysCount == 0 || len(caseCallExpr.Args) == 0 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect Case usage, key must be declared"),
+			Pos: caseCallExpr.Pos(),
+		}
+	}
+
+	caseComponents, err := NewComponentsFromArgs(caseCallExpr.Args[keysCount:], f)
 	if err != nil {
 		return nil, err
 	}
 
 	return &types.CaseComponent{
 		Tag:      caseCallExpr.Args[0],
+		Keys:     caseCallExpr.Args[1:keysCount],
 		Children: caseComponents,
 	}, nil
 }
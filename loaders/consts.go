@@ -7,10 +7,39 @@ const (
 	WrapExprType    = "Wrap"
 	BeforeExprType  = "Before"
 	SuccessExprType = "Success"
+	FinallyExprType = "Finally"
 
 	// decision
 	DecisionExprType = "Decision"
 	CaseExprType     = "Case"
+	DefaultExprType  = "Default"
+
+	// parallel
+	ParallelExprType = "Parallel"
+
+	// retry
+	RetryExprType    = "Retry"
+	AttemptsExprType = "Attempts"
+	BackoffExprType  = "Backoff"
+	RetryIfExprType  = "RetryIf"
+
+	// timeout
+	TimeoutExprType = "Timeout"
+
+	// for each
+	ForEachExprType     = "ForEach"
+	CollectExprType     = "Collect"
+	ConcurrencyExprType = "Concurrency"
+
+	// if
+	IfExprType   = "If"
+	ElseExprType = "Else"
+
+	// compensate
+	CompensateExprType = "Compensate"
+
+	// named values
+	AsExprType = "As"
 
 	//others
 	StepExprType    = "Step"
@@ -77,6 +77,26 @@ func LoadDecisionComponent(effeDecisionFuncCall *ast.CallExpr, f FlowLoader) (ty
 		decisionArgs = RemoveExprByIndex(decisionArgs, failureComponentIndex)
 	}
 
+	defaultCall, defaultIndex, err := FindCallExprWithType(decisionArgs, DefaultExprType)
+	if err != nil && err != ErrNoExpr {
+		return nil, err
+	} else if err == nil {
+		if len(defaultCall.Args) == 0 {
+			return nil, &types.LoadError{
+				Err: errors.New("incorrect Default usage, steps must be declared"),
+				Pos: defaultCall.Pos(),
+			}
+		}
+		defaultComponents, err := NewComponentsFromArgs(defaultCall.Args, f)
+		if err != nil {
+			return nil, err
+		}
+		decisionComponent.Default = &types.CaseComponent{
+			Children: defaultComponents,
+		}
+		decisionArgs = RemoveExprByIndex(decisionArgs, defaultIndex)
+	}
+
 	for _, arg := range decisionArgs {
 		caseCall, ok := arg.(*ast.CallExpr)
 		if !ok {
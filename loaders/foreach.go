@@ -0,0 +1,72 @@
+package loaders
+
+import (
+	"go/ast"
+
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// LoadForEachComponent converts an expression declared with effe.ForEach to a component with type types.ForEachComponent
+func LoadForEachComponent(effeForEachFuncCall *ast.CallExpr, f FlowLoader) (types.Component, error) {
+	if len(effeForEachFuncCall.Args) < 2 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect ForEach usage, args length must be more than 1"),
+			Pos: effeForEachFuncCall.Pos(),
+		}
+	}
+
+	sliceType, ok := typeFromNewCall(effeForEachFuncCall.Args[0])
+	arrayType, isArray := sliceType.(*ast.ArrayType)
+	if !ok || !isArray || arrayType.Len != nil {
+		return nil, &types.LoadError{
+			Err: errors.New("first arg must be a creation of slice with function new"),
+			Pos: effeForEachFuncCall.Args[0].Pos(),
+		}
+	}
+
+	forEach := &types.ForEachComponent{
+		SliceType: sliceType,
+		ItemType:  arrayType.Elt,
+	}
+
+	args := make([]ast.Expr, len(effeForEachFuncCall.Args)-1)
+	copy(args, effeForEachFuncCall.Args[1:])
+
+	var (
+		collectExpr ast.Expr
+		err         error
+	)
+	collectExpr, args, err = loadOptionExpr(args, CollectExprType)
+	if err != nil {
+		return nil, err
+	}
+	if collectExpr != nil {
+		forEach.Collect, ok = typeFromNewCall(collectExpr)
+		if !ok {
+			return nil, &types.LoadError{
+				Err: errors.New("incorrect Collect usage, arg must be a creation of type with function new"),
+				Pos: collectExpr.Pos(),
+			}
+		}
+	}
+
+	forEach.Concurrency, args, err = loadOptionExpr(args, ConcurrencyExprType)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect ForEach usage, steps must be declared"),
+			Pos: effeForEachFuncCall.Pos(),
+		}
+	}
+
+	forEach.Children, err = NewComponentsFromArgs(args, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return forEach, nil
+}
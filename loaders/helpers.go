@@ -16,7 +16,14 @@ func Default() map[string]ComponentLoadFunc {
 		FailureExprType:  LoadSimpleComponent,
 		BeforeExprType:   LoadSimpleComponent,
 		SuccessExprType:  LoadSimpleComponent,
+		FinallyExprType:  LoadSimpleComponent,
 		CaseExprType:     LoadCaseComponent,
+		ParallelExprType: LoadParallelComponent,
+		RetryExprType:    LoadRetryComponent,
+		RetryIfExprType:  LoadSimpleComponent,
+		TimeoutExprType:  LoadTimeoutComponent,
+		ForEachExprType:  LoadForEachComponent,
+		IfExprType:       LoadIfComponent,
 	}
 }
 
@@ -131,3 +138,35 @@ func NewComponentsFromArgs(args []ast.Expr, f FlowLoader) ([]types.Component, er
 	}
 	return components, nil
 }
+
+// loadOptionExpr searches an expression with a specific type, returns its argument
+// and removes the expression from args. If the expression is not found loadOptionExpr returns nil.
+func loadOptionExpr(args []ast.Expr, exprType string) (ast.Expr, []ast.Expr, error) {
+	optionCall, index, err := FindCallExprWithType(args, exprType)
+	if err == ErrNoExpr {
+		return nil, args, nil
+	} else if err != nil {
+		return nil, args, err
+	}
+
+	if len(optionCall.Args) != 1 {
+		return nil, args, &types.LoadError{
+			Err: errors.Errorf("incorrect %s usage, args length must be equal 1", exprType),
+			Pos: optionCall.Pos(),
+		}
+	}
+	return optionCall.Args[0], RemoveExprByIndex(args, index), nil
+}
+
+// typeFromNewCall returns a type from an expression new(T).
+func typeFromNewCall(expr ast.Expr) (ast.Expr, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, false
+	}
+	funcIdent, ok := call.Fun.(*ast.Ident)
+	if !ok || funcIdent.Name != "new" {
+		return nil, false
+	}
+	return call.Args[0], true
+}
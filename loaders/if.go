@@ -0,0 +1,75 @@
+package loaders
+
+import (
+	"go/ast"
+
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// LoadIfComponent converts an expression declared with effe.If to a component with type types.IfComponent
+func LoadIfComponent(effeIfFuncCall *ast.CallExpr, f FlowLoader) (types.Component, error) {
+	if len(effeIfFuncCall.Args) < 2 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect If usage, args length must be more than 1"),
+			Pos: effeIfFuncCall.Pos(),
+		}
+	}
+
+	predicateCall, ok := effeIfFuncCall.Args[0].(*ast.CallExpr)
+	if !ok {
+		return nil, &types.LoadError{
+			Err: errors.New("first arg must be a step"),
+			Pos: effeIfFuncCall.Args[0].Pos(),
+		}
+	}
+	predicate, err := f.LoadComponent(predicateCall)
+	if err != nil {
+		return nil, err
+	}
+	simplePredicate, ok := predicate.(*types.SimpleComponent)
+	if !ok {
+		return nil, &types.LoadError{
+			Err: errors.Errorf("predicate %s should be a simple component", predicate.Name()),
+			Pos: predicateCall.Pos(),
+		}
+	}
+
+	ifComponent := &types.IfComponent{
+		Predicate: simplePredicate,
+	}
+
+	args := make([]ast.Expr, len(effeIfFuncCall.Args)-1)
+	copy(args, effeIfFuncCall.Args[1:])
+
+	elseCall, index, err := FindCallExprWithType(args, ElseExprType)
+	if err != nil && err != ErrNoExpr {
+		return nil, err
+	} else if err == nil {
+		if len(elseCall.Args) == 0 {
+			return nil, &types.LoadError{
+				Err: errors.New("incorrect Else usage, steps must be declared"),
+				Pos: elseCall.Pos(),
+			}
+		}
+		ifComponent.Else, err = NewComponentsFromArgs(elseCall.Args, f)
+		if err != nil {
+			return nil, err
+		}
+		args = RemoveExprByIndex(args, index)
+	}
+
+	if len(args) == 0 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect If usage, steps must be declared"),
+			Pos: effeIfFuncCall.Pos(),
+		}
+	}
+
+	ifComponent.Then, err = NewComponentsFromArgs(args, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return ifComponent, nil
+}
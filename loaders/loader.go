@@ -2,6 +2,7 @@ package loaders
 
 import (
 	"go/ast"
+	goTypes "go/types"
 
 	"github.com/GettEngineering/effe/types"
 	"github.com/pkg/errors"
@@ -24,6 +25,10 @@ type loader struct {
 	loaders  map[string]ComponentLoadFunc
 	packages []string
 	decls    map[string]*ast.FuncDecl
+	// steps contains steps of a loaded flow for binding named values
+	steps []*types.SimpleComponent
+	// typesInfo is used for matching types of named values
+	typesInfo *goTypes.Info
 }
 
 // Initializes a new Loader
@@ -97,7 +102,17 @@ func (l *loader) LoadComponent(call *ast.CallExpr) (types.Component, error) {
 	}
 
 	handler, _ := l.getLoader(t)
-	return handler(call, l)
+	c, err := handler(call, l)
+	if err != nil {
+		return nil, err
+	}
+	if step, ok := c.(*types.SimpleComponent); ok {
+		l.steps = append(l.steps, step)
+		if step.Compensate != nil {
+			l.steps = append(l.steps, step.Compensate)
+		}
+	}
+	return c, nil
 }
 
 func (l loader) getLoader(name string) (ComponentLoadFunc, bool) {
@@ -106,6 +121,12 @@ func (l loader) getLoader(name string) (ComponentLoadFunc, bool) {
 }
 
 // Returns a declaration of function by name
+// SetTypesInfo sets information about types of a package which flows are loaded from.
+// Types are matched by string representations if the information isn't set.
+func (l *loader) SetTypesInfo(info *goTypes.Info) {
+	l.typesInfo = info
+}
+
 func (l loader) GetFuncDecl(name string) (*ast.FuncDecl, bool) {
 	v, ok := l.decls[name]
 	return v, ok
@@ -116,6 +137,7 @@ func (l loader) GetFuncDecl(name string) (*ast.FuncDecl, bool) {
 // LoadFlow returs nil in the second component.
 func (l *loader) LoadFlow(args []ast.Expr, decls map[string]*ast.FuncDecl) ([]types.Component, types.Component, error) {
 	l.decls = decls
+	l.steps = nil
 	failureComponent, failureIndex, err := genComponentFromArgsWithType(args, FailureExprType, l)
 	if err != nil && err != ErrNoExpr {
 		return nil, nil, err
@@ -127,6 +149,11 @@ func (l *loader) LoadFlow(args []ast.Expr, decls map[string]*ast.FuncDecl) ([]ty
 	if err != nil {
 		return nil, nil, err
 	}
+
+	err = bindValues(l.typesInfo, l.steps)
+	if err != nil {
+		return nil, nil, err
+	}
 	return components, failureComponent, nil
 }
 
@@ -137,6 +164,12 @@ type FlowLoader interface {
 	GetFuncDecl(string) (*ast.FuncDecl, bool)
 }
 
+// DSLCallChecker is an optional interface of FlowLoader.
+// It's used for finding where keys of effe.Case end.
+type DSLCallChecker interface {
+	IsDSLCall(ast.Expr) bool
+}
+
 // Type for loaders uses in Loader.
 type ComponentLoadFunc func(*ast.CallExpr, FlowLoader) (types.Component, error)
 
@@ -165,6 +198,20 @@ func getComponentType(stepFunc *ast.CallExpr, l *loader) (string, error) {
 	return effeStepFuncCall.Sel.Name, nil
 }
 
+// IsDSLCall checks that an expression is a call of a function from a registered package
+func (l loader) IsDSLCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	packageNameIdent, ok := selector.X.(*ast.Ident)
+	return ok && l.isRegisteredDSLPackage(packageNameIdent.Name)
+}
+
 func (l loader) isRegisteredDSLPackage(name string) bool {
 	for _, pkgName := range l.packages {
 		if pkgName == name {
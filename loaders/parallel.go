@@ -0,0 +1,27 @@
+package loaders
+
+import (
+	"go/ast"
+
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// LoadParallelComponent converts an expression declared with effe.Parallel to a component with type types.ParallelComponent
+func LoadParallelComponent(effeParallelFuncCall *ast.CallExpr, f FlowLoader) (types.Component, error) {
+	if len(effeParallelFuncCall.Args) < 2 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect Parallel usage, args length must be more than 1"),
+			Pos: effeParallelFuncCall.Pos(),
+		}
+	}
+
+	children, err := NewComponentsFromArgs(effeParallelFuncCall.Args, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ParallelComponent{
+		Children: children,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package loaders
+
+import (
+	"go/ast"
+
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// LoadRetryComponent converts an expression declared with effe.Retry to a component with type types.RetryComponent
+func LoadRetryComponent(effeRetryFuncCall *ast.CallExpr, f FlowLoader) (types.Component, error) {
+	if len(effeRetryFuncCall.Args) < 2 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect Retry usage, args length must be more than 1"),
+			Pos: effeRetryFuncCall.Pos(),
+		}
+	}
+	args := make([]ast.Expr, len(effeRetryFuncCall.Args))
+	copy(args, effeRetryFuncCall.Args)
+
+	retry := &types.RetryComponent{}
+
+	var err error
+	retry.Attempts, args, err = loadOptionExpr(args, AttemptsExprType)
+	if err != nil {
+		return nil, err
+	}
+	if retry.Attempts == nil {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect Retry usage, attempts must be declared"),
+			Pos: effeRetryFuncCall.Pos(),
+		}
+	}
+
+	retry.Backoff, args, err = loadOptionExpr(args, BackoffExprType)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceComponents, args, err := LoadComponentsWithTypes(args, f, RetryIfExprType)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := serviceComponents[RetryIfExprType]
+	if ok {
+		simple, ok := c.(*types.SimpleComponent)
+		if !ok {
+			return nil, &types.LoadError{
+				Err: errors.New("retry if function must be a function with a format as for step"),
+				Pos: effeRetryFuncCall.Pos(),
+			}
+		}
+		retry.RetryIf = simple
+	}
+
+	if len(args) != 1 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect Retry usage, only one step can be retried"),
+			Pos: effeRetryFuncCall.Pos(),
+		}
+	}
+
+	children, err := NewComponentsFromArgs(args, f)
+	if err != nil {
+		return nil, err
+	}
+	retry.Child = children[0]
+
+	return retry, nil
+}
@@ -2,7 +2,12 @@ package loaders
 
 import (
 	"go/ast"
+	"go/token"
+	goTypes "go/types"
+	"strconv"
+	"strings"
 
+	"github.com/GettEngineering/effe/fields"
 	"github.com/GettEngineering/effe/types"
 	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
@@ -16,59 +21,223 @@ func LoadSimpleComponent(effeStepFuncCall *ast.CallExpr, f FlowLoader) (types.Co
 			Pos: effeStepFuncCall.Pos(),
 		}
 	}
-	stepFuncCallIdent, ok := effeStepFuncCall.Args[0].(*ast.Ident)
+	pkgIdent, stepFuncCallIdent, ok := getStepFuncIdents(effeStepFuncCall.Args[0])
 	if !ok {
 		return nil, &types.LoadError{
 			Err: errors.New("arg is not an identifier of function"),
 			Pos: effeStepFuncCall.Pos(),
 		}
 	}
-	stepFuncCallDecl, ok := f.GetFuncDecl(stepFuncCallIdent.Name)
+	stepFuncName := goTypes.ExprString(effeStepFuncCall.Args[0])
+	stepFuncCallDecl, ok := f.GetFuncDecl(stepFuncName)
 	if !ok {
 		return nil, &types.LoadError{
-			Err: errors.Errorf("can't find a function with name %s", stepFuncCallIdent.Name),
+			Err: errors.Errorf("can't find a function with name %s", stepFuncName),
 			Pos: stepFuncCallIdent.Pos(),
 		}
 	}
 
-	var returnStmt *ast.ReturnStmt
-	for _, stmt := range stepFuncCallDecl.Body.List {
-		var tmpReturnStmt *ast.ReturnStmt
-		tmpReturnStmt, ok = stmt.(*ast.ReturnStmt)
-		if ok {
-			returnStmt = tmpReturnStmt
-			break
-		}
-	}
-	if returnStmt == nil {
-		return nil, &types.LoadError{
-			Err: errors.Errorf("function %s has incorrenct format: function must contain only return value", stepFuncCallIdent.Name),
-			Pos: stepFuncCallIdent.Pos(),
-		}
+	// A method expression like (*Service).Method is a step with the receiver as a dependency.
+	var receiver ast.Expr
+	if stepFuncCallDecl.Recv != nil {
+		receiver = stepFuncCallDecl.Type.Params.List[0].Type
+		pkgIdent = nil
 	}
+	serviceFuncName := ast.NewIdent(strcase.ToCamel(stepFuncNameReplacer.Replace(stepFuncName)))
 
-	if len(returnStmt.Results) == 0 {
+	stepFuncType, ok := getStepFuncType(stepFuncCallDecl)
+	if !ok {
 		return nil, &types.LoadError{
-			Err: errors.Errorf("function %s has incorrenct format: return value should be a function", stepFuncCallIdent.Name),
+			Err: errors.Errorf("function %s has incorrenct format: return value should be a function", stepFuncName),
 			Pos: stepFuncCallIdent.Pos(),
 		}
 	}
 
-	returnFuncLit, ok := returnStmt.Results[0].(*ast.FuncLit)
-	if !ok {
+	options := append([]ast.Expr{}, effeStepFuncCall.Args[1:]...)
+	name, options, err := loadValueName(options)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" && !returnsValue(stepFuncType.Results) {
 		return nil, &types.LoadError{
-			Err: errors.Errorf("function %s has incorrenct format: return value should be a function", stepFuncCallIdent.Name),
+			Err: errors.Errorf("step %s must return a value to declare a name %s", stepFuncName, name),
 			Pos: stepFuncCallIdent.Pos(),
 		}
 	}
 
-	serviceFuncName := ast.NewIdent(strcase.ToCamel(stepFuncCallIdent.Name))
+	compensate, err := loadCompensateComponent(options, f)
+	if err != nil {
+		return nil, err
+	}
 
 	return &types.SimpleComponent{
 		Deps:             stepFuncCallDecl.Type.Params,
 		FuncName:         serviceFuncName,
 		OriginalFuncName: stepFuncCallIdent,
-		Input:            returnFuncLit.Type.Params,
-		Output:           returnFuncLit.Type.Results,
+		Package:          pkgIdent,
+		Receiver:         receiver,
+		Input:            stepFuncType.Params,
+		Output:           stepFuncType.Results,
+		Compensate:       compensate,
+		As:               name,
 	}, nil
 }
+
+// getStepFuncType returns a signature of a function which is returned by a step.
+// If the step returns a function literal, then a signature of the literal is used,
+// otherwise the signature is taken from a declared result type of the step.
+// So the step can contain setup code and return any function value.
+func getStepFuncType(decl *ast.FuncDecl) (*ast.FuncType, bool) {
+	if decl.Body != nil {
+		for _, stmt := range decl.Body.List {
+			returnStmt, ok := stmt.(*ast.ReturnStmt)
+			if !ok || len(returnStmt.Results) != 1 {
+				continue
+			}
+			if funcLit, ok := returnStmt.Results[0].(*ast.FuncLit); ok {
+				return nameArgs(funcLit.Type), true
+			}
+		}
+	}
+
+	if decl.Type.Results == nil || len(decl.Type.Results.List) != 1 {
+		return nil, false
+	}
+	funcType, ok := decl.Type.Results.List[0].Type.(*ast.FuncType)
+	if !ok {
+		return nil, false
+	}
+	return nameArgs(funcType), true
+}
+
+// nameArgs names arguments of a function without names, because
+// the arguments are passed to the function in generated code.
+func nameArgs(funcType *ast.FuncType) *ast.FuncType {
+	if funcType.Params == nil {
+		return funcType
+	}
+
+	var hasArgsWithoutNames bool
+	for _, param := range funcType.Params.List {
+		if len(param.Names) == 0 {
+			hasArgsWithoutNames = true
+		}
+		for _, name := range param.Names {
+			if name.Name == "_" {
+				hasArgsWithoutNames = true
+			}
+		}
+	}
+	if !hasArgsWithoutNames {
+		return funcType
+	}
+
+	params := &ast.FieldList{}
+	for _, param := range funcType.Params.List {
+		names := make([]*ast.Ident, 0, len(param.Names))
+		for _, name := range param.Names {
+			if name.Name == "_" {
+				name = ast.NewIdent("arg" + strconv.Itoa(len(params.List)+len(names)+1))
+			}
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			names = append(names, ast.NewIdent("arg"+strconv.Itoa(len(params.List)+1)))
+		}
+		params.List = append(params.List, &ast.Field{Names: names, Type: param.Type})
+	}
+	return &ast.FuncType{Params: params, Results: funcType.Results}
+}
+
+var stepFuncNameReplacer = strings.NewReplacer("(", "", "*", "", ")", "", ".", "_")
+
+// getStepFuncIdents returns identifiers of a function which is declared as a step.
+// The function can be declared as f, pkg.F, T.Method, (*T).Method, pkg.T.Method or (*pkg.T).Method.
+// A package identifier is nil for functions from the current package.
+func getStepFuncIdents(expr ast.Expr) (*ast.Ident, *ast.Ident, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return nil, expr, true
+	case *ast.SelectorExpr:
+		x := expr.X
+		if paren, ok := x.(*ast.ParenExpr); ok {
+			star, ok := paren.X.(*ast.StarExpr)
+			if !ok {
+				return nil, nil, false
+			}
+			x = star.X
+		}
+		switch x := x.(type) {
+		case *ast.Ident:
+			return x, expr.Sel, true
+		case *ast.SelectorExpr:
+			if _, ok := x.X.(*ast.Ident); ok {
+				return nil, expr.Sel, true
+			}
+		}
+		return nil, nil, false
+	default:
+		return nil, nil, false
+	}
+}
+
+// loadValueName loads a name of values declared with effe.As in options of a step.
+// If the name is not declared loadValueName returns an empty string.
+func loadValueName(options []ast.Expr) (string, []ast.Expr, error) {
+	nameExpr, options, err := loadOptionExpr(options, AsExprType)
+	if err != nil || nameExpr == nil {
+		return "", options, err
+	}
+
+	nameLit, ok := nameExpr.(*ast.BasicLit)
+	if !ok || nameLit.Kind != token.STRING {
+		return "", options, &types.LoadError{
+			Err: errors.New("incorrect As usage, name must be a string literal"),
+			Pos: nameExpr.Pos(),
+		}
+	}
+	name, err := strconv.Unquote(nameLit.Value)
+	if err != nil || !token.IsIdentifier(name) {
+		return "", options, &types.LoadError{
+			Err: errors.Errorf("incorrect As usage, name %s must be a valid identifier", nameLit.Value),
+			Pos: nameExpr.Pos(),
+		}
+	}
+	return name, options, nil
+}
+
+func returnsValue(output *ast.FieldList) bool {
+	if output == nil {
+		return false
+	}
+	for _, outputField := range output.List {
+		if fields.GetTypeStrName(outputField.Type) != "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCompensateComponent loads a compensation declared with effe.Compensate in options of a step.
+// If the compensation is not declared loadCompensateComponent returns nil.
+func loadCompensateComponent(options []ast.Expr, f FlowLoader) (*types.SimpleComponent, error) {
+	if len(options) == 0 {
+		return nil, nil
+	}
+
+	compensateCall, _, err := FindCallExprWithType(options, CompensateExprType)
+	if err == ErrNoExpr || len(options) > 1 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect Step usage, only Compensate and As can be declared as options"),
+			Pos: options[0].Pos(),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	c, err := LoadSimpleComponent(compensateCall, f)
+	if err != nil {
+		return nil, err
+	}
+	return c.(*types.SimpleComponent), nil
+}
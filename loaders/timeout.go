@@ -0,0 +1,28 @@
+package loaders
+
+import (
+	"go/ast"
+
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// LoadTimeoutComponent converts an expression declared with effe.Timeout to a component with type types.TimeoutComponent
+func LoadTimeoutComponent(effeTimeoutFuncCall *ast.CallExpr, f FlowLoader) (types.Component, error) {
+	if len(effeTimeoutFuncCall.Args) < 2 {
+		return nil, &types.LoadError{
+			Err: errors.New("incorrect Timeout usage, args length must be more than 1"),
+			Pos: effeTimeoutFuncCall.Pos(),
+		}
+	}
+
+	children, err := NewComponentsFromArgs(effeTimeoutFuncCall.Args[1:], f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TimeoutComponent{
+		Duration: effeTimeoutFuncCall.Args[0],
+		Children: children,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package loaders
+
+import (
+	"go/ast"
+	goTypes "go/types"
+	"sort"
+	"strings"
+
+	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
+)
+
+// bindValues binds arguments of steps to named values declared with effe.As.
+// An argument takes a named value if the argument has the same name and type.
+// Types are compared by keys from fields.GetTypeKey.
+// If a flow has only named values with a type of an argument and a name of the argument
+// doesn't match any of them, then the argument is ambiguous and bindValues returns an error.
+func bindValues(info *goTypes.Info, steps []*types.SimpleComponent) error {
+	namedValues := make(map[string]map[string]struct{})
+	unnamedValues := make(map[string]struct{})
+	for _, step := range steps {
+		if step.Output == nil {
+			continue
+		}
+		for _, outputField := range step.Output.List {
+			if fields.GetTypeStrName(outputField.Type) == "error" {
+				continue
+			}
+			typeKey := fields.GetTypeKey(info, outputField.Type)
+			if step.As == "" {
+				unnamedValues[typeKey] = struct{}{}
+				continue
+			}
+			if namedValues[typeKey] == nil {
+				namedValues[typeKey] = make(map[string]struct{})
+			}
+			namedValues[typeKey][step.As] = struct{}{}
+		}
+	}
+
+	for _, step := range steps {
+		if step.Input == nil {
+			continue
+		}
+		for _, inputField := range step.Input.List {
+			typeKey := fields.GetTypeKey(info, inputField.Type)
+			names, ok := namedValues[typeKey]
+			if !ok {
+				continue
+			}
+			_, hasUnnamedValue := unnamedValues[typeKey]
+
+			argNames := inputField.Names
+			if len(argNames) == 0 {
+				argNames = []*ast.Ident{ast.NewIdent("_")}
+			}
+			for _, argName := range argNames {
+				if _, ok = names[argName.Name]; ok {
+					step.NamedInputs = append(step.NamedInputs, argName.Name)
+					continue
+				}
+				if !hasUnnamedValue {
+					return &types.LoadError{
+						Err: errors.Errorf(
+							"argument %s with type %s of step %s is ambiguous, rename it to one of named values: %s",
+							argName.Name, fields.GetTypeStrName(inputField.Type), step.OriginalFuncName.Name, strings.Join(sortedNames(names), ", "),
+						),
+						Pos: step.OriginalFuncName.Pos(),
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func sortedNames(names map[string]struct{}) []string {
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
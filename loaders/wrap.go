@@ -3,6 +3,7 @@ package loaders
 import (
 	"go/ast"
 
+	"github.com/GettEngineering/effe/fields"
 	"github.com/GettEngineering/effe/types"
 	"github.com/pkg/errors"
 )
@@ -16,7 +17,7 @@ func LoadWrapComponent(effeWrapFuncCall *ast.CallExpr, f FlowLoader) (types.Comp
 		}
 	}
 
-	serviceComponents, args, err := LoadComponentsWithTypes(effeWrapFuncCall.Args, f, SuccessExprType, FailureExprType, BeforeExprType)
+	serviceComponents, args, err := LoadComponentsWithTypes(effeWrapFuncCall.Args, f, SuccessExprType, FailureExprType, BeforeExprType, FinallyExprType)
 
 	if err != nil {
 		return nil, err
@@ -72,5 +73,63 @@ func LoadWrapComponent(effeWrapFuncCall *ast.CallExpr, f FlowLoader) (types.Comp
 		}
 		wrap.Failure = simple
 	}
+
+	c, ok = serviceComponents[FinallyExprType]
+	if ok {
+		simple, ok = c.(*types.SimpleComponent)
+		if !ok {
+			return nil, &types.LoadError{
+				Err: errors.New("finally function must be a function with a format as for step"),
+				Pos: effeWrapFuncCall.Pos(),
+			}
+		}
+		wrap.Finally = simple
+		if err := checkFinally(wrap); err != nil {
+			return nil, &types.LoadError{
+				Err: err,
+				Pos: effeWrapFuncCall.Pos(),
+			}
+		}
+	}
 	return wrap, nil
 }
+
+// checkFinally checks that the finally function is called regardless of a result.
+// The function is deferred right after steps which return its arguments,
+// so these steps and steps before them must not fail.
+func checkFinally(wrap *types.WrapComponent) error {
+	components := make([]types.Component, 0)
+	if wrap.Before != nil {
+		components = append(components, wrap.Before)
+	}
+	components = append(components, wrap.Children...)
+
+	var index int
+	for componentIndex, component := range components {
+		simple, ok := component.(*types.SimpleComponent)
+		if !ok || simple.Output == nil || wrap.Finally.Input == nil {
+			continue
+		}
+		for _, input := range wrap.Finally.Input.List {
+			if fields.FindFieldWithType(simple.Output.List, input.Type) != nil {
+				index = componentIndex + 1
+			}
+		}
+	}
+
+	for _, component := range components[:index] {
+		simple, ok := component.(*types.SimpleComponent)
+		if !ok {
+			return errors.Errorf("finally function %s is deferred after component %s, which can fail", wrap.Finally.Name(), component.Name())
+		}
+		if simple.Output == nil {
+			continue
+		}
+		for _, output := range simple.Output.List {
+			if fields.GetTypeStrName(output.Type) == "error" {
+				return errors.Errorf("finally function %s is deferred after step %s, which can fail", wrap.Finally.Name(), simple.Name())
+			}
+		}
+	}
+	return nil
+}
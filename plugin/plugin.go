@@ -4,6 +4,7 @@ package plugin
 
 import (
 	"go/ast"
+	"go/types"
 )
 
 // Context interface uses for searching variable in stack of variables and adding
@@ -14,6 +15,39 @@ type Context interface {
 	OutputList() []*ast.Field
 }
 
+// FlowContext is an optional interface of Context with information about the generated flow.
+// Contexts, which are passed by strategies of effe, implement it.
+type FlowContext interface {
+	Context
+
+	// Name of the generated flow
+	FlowName() string
+
+	// Returns a new variable for a type with a unique name in the generated flow
+	NewVar(ast.Expr) *ast.Ident
+}
+
+// FlowName returns a name of the generated flow or an empty string,
+// if a context doesn't implement FlowContext.
+func FlowName(ctx Context) string {
+	if flowCtx, ok := ctx.(FlowContext); ok {
+		return flowCtx.FlowName()
+	}
+	return ""
+}
+
+// StepContext is an optional interface of Context, which is passed to hooks of a component.
+// Contexts, which are passed by strategies of effe, implement it.
+type StepContext interface {
+	FlowContext
+
+	// Component statement for which hooks are called
+	Step() ComponentStmt
+
+	// Types of expressions of a package with the flow. It can be nil.
+	TypesInfo() *types.Info
+}
+
 // ComponentStmt interface is used by a plugin
 type ComponentStmt interface {
 	// Generated code for calling. It can be ast.AssignStmt or  ast.ExprStmt
@@ -32,6 +66,18 @@ type ComponentStmt interface {
 	Name() string
 }
 
+// DeclaredFieldsStmt is an optional interface of ComponentStmt with parameters and results
+// of a component as they are declared, for example with names of parameters of a step.
+type DeclaredFieldsStmt interface {
+	ComponentStmt
+
+	// Parameters of a component, they are in the same order as InputFields()
+	DeclaredInputFields() []*ast.Field
+
+	// Results of a component, they are in the same order as OutputFields()
+	DeclaredOutputFields() []*ast.Field
+}
+
 // Plugin interface that must be implemented by plugins
 type Plugin interface {
 
@@ -56,3 +102,32 @@ type Plugin interface {
 	// Return value is a mark - code changed or not. If code has changes then imports are added.
 	Change(Context, ComponentStmt) bool
 }
+
+// FlowPlugin is an optional interface of a plugin with hooks for a whole flow.
+// BeforeFlow is called once for a generated flow function, AfterFlow is called
+// for every return statement of the flow function.
+type FlowPlugin interface {
+	// Hook for adding new statements at the beginning of a flow function.
+	//
+	// Second argument - flow name
+	BeforeFlow(Context, string) []ast.Stmt
+
+	// Hook for adding new statements before a return of a flow function. A flow returns
+	// after a failure too, in this case an error variable is passed in result variables.
+	//
+	// Second argument - flow name
+	//
+	// Third argument - result variables of a flow, results without variables don't have names
+	AfterFlow(Context, string, []*ast.Field) []ast.Stmt
+}
+
+// FailurePlugin is an optional interface of a plugin with a hook for failed components.
+type FailurePlugin interface {
+	// Hook for adding new statements when a component returns an error.
+	// Statements are added before failure statements of the component.
+	//
+	// Second argument - component name
+	//
+	// Third argument - error variable
+	OnFailure(Context, string, *ast.Ident) []ast.Stmt
+}
@@ -0,0 +1,150 @@
+package plugins
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"time"
+
+	"github.com/GettEngineering/effe/plugin"
+)
+
+// This type is used by metrics plugin. Prometheus implementation can be initialized
+// with prommetrics.New from the module github.com/GettEngineering/effe/plugins/prommetrics
+type Metrics interface {
+	// Called after a successful call of a step
+	ObserveSuccess(flow, step string, duration time.Duration)
+
+	// Called after a failed call of a step before a failure component
+	ObserveFailure(flow, step string, duration time.Duration)
+}
+
+type metrics struct {
+	// startTime is a variable with a start time of a component, it's declared
+	// in Before and used in Change and Success for the same component.
+	startTime *ast.Ident
+}
+
+func (m *metrics) buildMetricsType() ast.Expr {
+	return &ast.SelectorExpr{
+		X:   ast.NewIdent("plugins"),
+		Sel: ast.NewIdent("Metrics"),
+	}
+}
+
+func (m *metrics) buildTimeType() ast.Expr {
+	return &ast.SelectorExpr{
+		X:   ast.NewIdent("time"),
+		Sel: ast.NewIdent("Time"),
+	}
+}
+
+func (m *metrics) metricsVariable(ctx plugin.Context) *ast.Field {
+	mVariable := ctx.FindInputByType(m.buildMetricsType())
+	if mVariable == nil {
+		mVariable = ctx.AddInput(m.buildMetricsType())
+	}
+	return mVariable
+}
+
+func (m *metrics) buildObserveStmt(ctx plugin.Context, method, name string) ast.Stmt {
+	return &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   m.metricsVariable(ctx).Names[0],
+				Sel: ast.NewIdent(method),
+			},
+			Args: []ast.Expr{
+				&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", plugin.FlowName(ctx))},
+				&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", name)},
+				&ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X:   ast.NewIdent("time"),
+						Sel: ast.NewIdent("Since"),
+					},
+					Args: []ast.Expr{m.startTime},
+				},
+			},
+		},
+	}
+}
+
+// Before declares a variable with a start time of the component. Components aren't measured,
+// if a context doesn't implement plugin.FlowContext, because the variable can't be declared.
+func (m *metrics) Before(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+	flowCtx, ok := ctx.(plugin.FlowContext)
+	if !ok {
+		m.startTime = nil
+		return []ast.Stmt{}
+	}
+	m.metricsVariable(ctx)
+	m.startTime = flowCtx.NewVar(m.buildTimeType())
+
+	return []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{m.startTime},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{
+				&ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X:   ast.NewIdent("time"),
+						Sel: ast.NewIdent("Now"),
+					},
+				},
+			},
+		},
+	}
+}
+
+// Change observes a failure before failure statements of the component.
+func (m *metrics) Change(ctx plugin.Context, c plugin.ComponentStmt) bool {
+	if m.startTime == nil || c.ErrStmt() == nil {
+		return false
+	}
+
+	var changed bool
+	for _, stmt := range c.ErrStmt().List {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		ifStmt.Body.List = append([]ast.Stmt{m.buildObserveStmt(ctx, "ObserveFailure", c.Name())}, ifStmt.Body.List...)
+		changed = true
+	}
+	return changed
+}
+
+func (m *metrics) Success(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+	if m.startTime == nil {
+		return []ast.Stmt{}
+	}
+	return []ast.Stmt{m.buildObserveStmt(ctx, "ObserveSuccess", name)}
+}
+
+func (m *metrics) Imports() []string {
+	return []string{
+		"time",
+		"github.com/GettEngineering/effe/plugins",
+	}
+}
+
+// Initializes metrics plugin. The plugin measures a duration of every component and passes
+// it to plugins.Metrics with a name of a flow and a name of a component.
+//
+// Example of generated code:
+//
+//      func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+//          return func(metricsVal plugins.Metrics) error {
+//              timeVal := time.Now()
+//              err := service.Step1()
+//              if err != nil {
+//                  metricsVal.ObserveFailure("BuildComponent2", "Step1", time.Since(timeVal))
+//                  return err
+//              }
+//              metricsVal.ObserveSuccess("BuildComponent2", "Step1", time.Since(timeVal))
+//              return nil
+//          }
+//      }
+func NewMetrics() plugin.Plugin {
+	return &metrics{}
+}
@@ -0,0 +1,243 @@
+package plugins
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/plugin"
+)
+
+const (
+	// OtelTracerName is a name of a tracer which starts spans for components
+	OtelTracerName = "github.com/GettEngineering/effe"
+
+	// OtelFlowAttribute is an attribute of a span with a name of a flow
+	OtelFlowAttribute = "effe.flow"
+)
+
+type otelTracing struct {
+	// span is a variable of a span for a component, it's declared in Before
+	// and used in Change and Success for the same component.
+	span *ast.Ident
+	// spanCtx is a variable of a context with the span
+	spanCtx *ast.Ident
+	// failed is true if a failure block of a component records an error
+	failed bool
+}
+
+func (o *otelTracing) buildContextType() ast.Expr {
+	return &ast.SelectorExpr{
+		X:   ast.NewIdent("context"),
+		Sel: ast.NewIdent("Context"),
+	}
+}
+
+func (o *otelTracing) buildSpanType() ast.Expr {
+	return &ast.SelectorExpr{
+		X:   ast.NewIdent("trace"),
+		Sel: ast.NewIdent("Span"),
+	}
+}
+
+// Before starts a span of the component. Spans aren't started, if a context doesn't
+// implement plugin.FlowContext, because variables of the span can't be declared.
+func (o *otelTracing) Before(ctx plugin.Context, name string, inputFields []*ast.Field) []ast.Stmt {
+	o.failed = false
+	flowCtx, ok := ctx.(plugin.FlowContext)
+	if !ok {
+		o.span = nil
+		return []ast.Stmt{}
+	}
+
+	var parentCtx *ast.Ident
+	for _, field := range inputFields {
+		if fields.GetTypeStrName(field.Type) == "context.Context" {
+			parentCtx = field.Names[0]
+			break
+		}
+	}
+
+	// A context of the component is replaced with a context of the span. If the component
+	// doesn't take a context, then the span is started with a context of the flow.
+	o.spanCtx = ast.NewIdent("_")
+	if parentCtx != nil {
+		o.spanCtx = flowCtx.NewVar(o.buildContextType())
+	} else {
+		parentCtx = ctx.AddInput(o.buildContextType()).Names[0]
+	}
+	o.span = flowCtx.NewVar(o.buildSpanType())
+
+	return []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{o.spanCtx, o.span},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{
+				&ast.CallExpr{
+					Fun: &ast.SelectorExpr{
+						X: &ast.CallExpr{
+							Fun: &ast.SelectorExpr{
+								X:   ast.NewIdent("otel"),
+								Sel: ast.NewIdent("Tracer"),
+							},
+							Args: []ast.Expr{
+								&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", OtelTracerName)},
+							},
+						},
+						Sel: ast.NewIdent("Start"),
+					},
+					Args: []ast.Expr{
+						parentCtx,
+						&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", name)},
+						&ast.CallExpr{
+							Fun: &ast.SelectorExpr{
+								X:   ast.NewIdent("trace"),
+								Sel: ast.NewIdent("WithAttributes"),
+							},
+							Args: []ast.Expr{
+								&ast.CallExpr{
+									Fun: &ast.SelectorExpr{
+										X:   ast.NewIdent("attribute"),
+										Sel: ast.NewIdent("String"),
+									},
+									Args: []ast.Expr{
+										&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", OtelFlowAttribute)},
+										&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", flowCtx.FlowName())},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Change passes the context of the span to the component and records an error in the span.
+func (o *otelTracing) Change(ctx plugin.Context, componentStmt plugin.ComponentStmt) bool {
+	if o.span == nil {
+		return false
+	}
+	if o.spanCtx.Name != "_" {
+		o.replaceContext(componentStmt.Stmt(), componentStmt.InputFields())
+	}
+
+	if componentStmt.ErrStmt() == nil {
+		return false
+	}
+	for _, stmt := range componentStmt.ErrStmt().List {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+		if !ok {
+			continue
+		}
+		errVar, ok := cond.X.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		ifStmt.Body.List = append(o.buildFailureStmts(errVar), ifStmt.Body.List...)
+		o.failed = true
+	}
+	return o.failed
+}
+
+func (o *otelTracing) replaceContext(stmt ast.Stmt, inputFields []*ast.Field) {
+	var call *ast.CallExpr
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		call, _ = stmt.Rhs[0].(*ast.CallExpr)
+	case *ast.ExprStmt:
+		call, _ = stmt.X.(*ast.CallExpr)
+	}
+	if call == nil {
+		return
+	}
+
+	for index, field := range inputFields {
+		if index < len(call.Args) && fields.GetTypeStrName(field.Type) == "context.Context" {
+			call.Args[index] = o.spanCtx
+		}
+	}
+}
+
+func (o *otelTracing) buildFailureStmts(errVar *ast.Ident) []ast.Stmt {
+	return []ast.Stmt{
+		&ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun:  &ast.SelectorExpr{X: o.span, Sel: ast.NewIdent("RecordError")},
+				Args: []ast.Expr{errVar},
+			},
+		},
+		&ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: o.span, Sel: ast.NewIdent("SetStatus")},
+				Args: []ast.Expr{
+					&ast.SelectorExpr{X: ast.NewIdent("codes"), Sel: ast.NewIdent("Error")},
+					&ast.CallExpr{
+						Fun: &ast.SelectorExpr{X: errVar, Sel: ast.NewIdent("Error")},
+					},
+				},
+			},
+		},
+		o.buildEndStmt(),
+	}
+}
+
+func (o *otelTracing) buildEndStmt() ast.Stmt {
+	return &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{X: o.span, Sel: ast.NewIdent("End")},
+		},
+	}
+}
+
+func (o *otelTracing) Success(ctx plugin.Context, name string, outputFields []*ast.Field) []ast.Stmt {
+	if o.span == nil {
+		return []ast.Stmt{}
+	}
+	return []ast.Stmt{o.buildEndStmt()}
+}
+
+// Imports returns imports for the last component. The package with status codes
+// is imported only if the component records errors.
+func (o *otelTracing) Imports() []string {
+	imports := []string{
+		"context",
+		"go.opentelemetry.io/otel",
+		"go.opentelemetry.io/otel/attribute",
+		"go.opentelemetry.io/otel/trace",
+	}
+	if o.failed {
+		imports = append(imports, "go.opentelemetry.io/otel/codes")
+	}
+	return imports
+}
+
+// Initializes OpenTelemetry tracing plugin. The plugin starts a span for every component
+// with a global tracer provider, passes a context of the span to the component and ends the span.
+// An error of the component is recorded in the span.
+//
+// Example of generated code:
+//
+//      func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+//          return func(ctx context.Context) error {
+//              ctx2, spanVal := otel.Tracer("github.com/GettEngineering/effe").Start(ctx, "Step1", trace.WithAttributes(attribute.String("effe.flow", "BuildComponent2")))
+//              err := service.Step1(ctx2)
+//              if err != nil {
+//                  spanVal.RecordError(err)
+//                  spanVal.SetStatus(codes.Error, err.Error())
+//                  spanVal.End()
+//                  return err
+//              }
+//              spanVal.End()
+//              return nil
+//          }
+//      }
+func NewOtelTracing() plugin.Plugin {
+	return &otelTracing{}
+}
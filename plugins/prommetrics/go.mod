@@ -0,0 +1,10 @@
+module github.com/GettEngineering/effe/plugins/prommetrics
+
+go 1.14
+
+require (
+	github.com/GettEngineering/effe v0.0.0
+	github.com/prometheus/client_golang v1.7.0
+)
+
+replace github.com/GettEngineering/effe => ../..
@@ -0,0 +1,64 @@
+// Package prommetrics implements plugins.Metrics with Prometheus collectors.
+// It's a separate module, so the effe module doesn't depend on a Prometheus client.
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/GettEngineering/effe/plugins"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	flowLabel = "flow"
+	stepLabel = "step"
+)
+
+type prometheusMetrics struct {
+	duration *prometheus.HistogramVec
+	success  *prometheus.CounterVec
+	failure  *prometheus.CounterVec
+}
+
+func (p *prometheusMetrics) ObserveSuccess(flow, step string, duration time.Duration) {
+	p.duration.WithLabelValues(flow, step).Observe(duration.Seconds())
+	p.success.WithLabelValues(flow, step).Inc()
+}
+
+func (p *prometheusMetrics) ObserveFailure(flow, step string, duration time.Duration) {
+	p.duration.WithLabelValues(flow, step).Observe(duration.Seconds())
+	p.failure.WithLabelValues(flow, step).Inc()
+}
+
+// New creates collectors and registers them in a registerer. Metrics are labelled by flow and step:
+//
+//      <namespace>_step_duration_seconds - histogram of durations of steps
+//      <namespace>_step_success_total - counter of successful calls of steps
+//      <namespace>_step_failures_total - counter of failed calls of steps
+func New(registerer prometheus.Registerer, namespace string) (plugins.Metrics, error) {
+	labels := []string{flowLabel, stepLabel}
+	p := &prometheusMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "step_duration_seconds",
+			Help:      "Duration of calls of steps in seconds.",
+		}, labels),
+		success: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "step_success_total",
+			Help:      "Number of successful calls of steps.",
+		}, labels),
+		failure: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "step_failures_total",
+			Help:      "Number of failed calls of steps.",
+		}, labels),
+	}
+
+	for _, collector := range []prometheus.Collector{p.duration, p.success, p.failure} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
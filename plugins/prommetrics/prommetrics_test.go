@@ -0,0 +1,85 @@
+package prommetrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNewRegistersCollectors(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	metrics, err := New(registry, "payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metrics.ObserveSuccess("Charge", "Step1", 2*time.Second)
+	metrics.ObserveFailure("Charge", "Step2", time.Second)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	want := []string{
+		"payments_step_duration_seconds",
+		"payments_step_failures_total",
+		"payments_step_success_total",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got metrics %v, want %v", names, want)
+	}
+}
+
+func TestLabelValues(t *testing.T) {
+	metrics, err := New(prometheus.NewRegistry(), "payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := metrics.(*prometheusMetrics)
+
+	metrics.ObserveSuccess("Charge", "Step1", 2*time.Second)
+	metrics.ObserveSuccess("Charge", "Step1", time.Second)
+	metrics.ObserveFailure("Charge", "Step2", time.Second)
+
+	counters := []struct {
+		name    string
+		counter prometheus.Counter
+		want    float64
+	}{
+		{"success Step1", p.success.WithLabelValues("Charge", "Step1"), 2},
+		{"failures Step1", p.failure.WithLabelValues("Charge", "Step1"), 0},
+		{"success Step2", p.success.WithLabelValues("Charge", "Step2"), 0},
+		{"failures Step2", p.failure.WithLabelValues("Charge", "Step2"), 1},
+	}
+	for _, c := range counters {
+		if got := testutil.ToFloat64(c.counter); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+	if got := testutil.CollectAndCount(p.duration); got != 2 {
+		t.Errorf("got %d histograms of durations, want 2", got)
+	}
+}
+
+func TestNewDuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := New(registry, "payments"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := New(registry, "payments")
+	if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+		t.Errorf("got error %v, want prometheus.AlreadyRegisteredError", err)
+	}
+
+	if _, err := New(registry, "orders"); err != nil {
+		t.Errorf("collectors with another namespace aren't registered: %v", err)
+	}
+}
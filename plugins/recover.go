@@ -0,0 +1,167 @@
+package plugins
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/plugin"
+)
+
+const (
+	// recoveredErrName is a name of an error result of a closure. Generated variables
+	// always have a suffix or a short name, so the name doesn't shadow arguments of a call.
+	recoveredErrName = "panicErr"
+	recoveredVarName = "r"
+)
+
+type recoverPanic struct{}
+
+func (r recoverPanic) Before(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+	return []ast.Stmt{}
+}
+
+func (r recoverPanic) Success(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+	return []ast.Stmt{}
+}
+
+// buildDeferStmt builds a deferred function which converts a panic into an error
+func (r recoverPanic) buildDeferStmt(name string) ast.Stmt {
+	return &ast.DeferStmt{
+		Call: &ast.CallExpr{
+			Fun: &ast.FuncLit{
+				Type: &ast.FuncType{Params: &ast.FieldList{}},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						&ast.IfStmt{
+							Init: &ast.AssignStmt{
+								Lhs: []ast.Expr{ast.NewIdent(recoveredVarName)},
+								Tok: token.DEFINE,
+								Rhs: []ast.Expr{&ast.CallExpr{Fun: ast.NewIdent("recover")}},
+							},
+							Cond: &ast.BinaryExpr{
+								X:  ast.NewIdent(recoveredVarName),
+								Op: token.NEQ,
+								Y:  ast.NewIdent("nil"),
+							},
+							Body: &ast.BlockStmt{
+								List: []ast.Stmt{
+									&ast.AssignStmt{
+										Lhs: []ast.Expr{ast.NewIdent(recoveredErrName)},
+										Tok: token.ASSIGN,
+										Rhs: []ast.Expr{
+											&ast.CallExpr{
+												Fun: &ast.SelectorExpr{
+													X:   ast.NewIdent("fmt"),
+													Sel: ast.NewIdent("Errorf"),
+												},
+												Args: []ast.Expr{
+													&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", "panic in step "+name+": %v\n%s")},
+													ast.NewIdent(recoveredVarName),
+													&ast.CallExpr{
+														Fun: &ast.SelectorExpr{
+															X:   ast.NewIdent("debug"),
+															Sel: ast.NewIdent("Stack"),
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Change wraps a call of the component in a closure which recovers a panic. The panic is returned
+// as an error, so failure statements of the component are called. Components without an error
+// in results aren't changed, because the error can't be handled.
+func (r recoverPanic) Change(ctx plugin.Context, componentStmt plugin.ComponentStmt) bool {
+	if componentStmt.ErrStmt() == nil {
+		return false
+	}
+	assignStmt, ok := componentStmt.Stmt().(*ast.AssignStmt)
+	if !ok || len(assignStmt.Rhs) != 1 {
+		return false
+	}
+	call, ok := assignStmt.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	errIndex := -1
+	for index, field := range componentStmt.OutputFields() {
+		if fields.GetTypeStrName(field.Type) == "error" {
+			errIndex = index
+		}
+	}
+	if errIndex == -1 {
+		return false
+	}
+
+	results := &ast.FieldList{}
+	for index, field := range componentStmt.OutputFields() {
+		name := ast.NewIdent("_")
+		if index == errIndex {
+			name = ast.NewIdent(recoveredErrName)
+		}
+		results.List = append(results.List, &ast.Field{
+			Names: []*ast.Ident{name},
+			Type:  field.Type,
+		})
+	}
+
+	assignStmt.Rhs[0] = &ast.CallExpr{
+		Fun: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  &ast.FieldList{},
+				Results: results,
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					r.buildDeferStmt(componentStmt.Name()),
+					&ast.ReturnStmt{Results: []ast.Expr{call}},
+				},
+			},
+		},
+	}
+	return true
+}
+
+func (r recoverPanic) Imports() []string {
+	return []string{
+		"fmt",
+		"runtime/debug",
+	}
+}
+
+// Initializes panic recovery plugin. The plugin converts a panic of a component into an error
+// with a name of the component and a stack. The error is handled as an error returned by the component.
+//
+// Example of generated code:
+//
+//      func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+//          return func() error {
+//              err := func() (panicErr error) {
+//                  defer func() {
+//                      if r := recover(); r != nil {
+//                          panicErr = fmt.Errorf("panic in step Step1: %v\n%s", r, debug.Stack())
+//                      }
+//                  }()
+//                  return service.Step1()
+//              }()
+//              if err != nil {
+//                  return err
+//              }
+//              return nil
+//          }
+//      }
+func NewRecoverPlugin() plugin.Plugin {
+	return &recoverPanic{}
+}
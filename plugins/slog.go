@@ -0,0 +1,280 @@
+package plugins
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+
+	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/plugin"
+)
+
+const (
+	// SlogRedactedValue replaces values of redacted types in logs
+	SlogRedactedValue = "[REDACTED]"
+
+	slogLibrary = "log/slog"
+)
+
+// SlogOption configures the slog plugin
+type SlogOption func(*slogPlugin)
+
+// WithSlogRedactedTypes logs values of types as SlogRedactedValue.
+// Types are written as in a source code with a name of a package, for example *models.User,
+// or with an import path, for example *github.com/acme/app/models.User. Aliases of types are redacted too.
+func WithSlogRedactedTypes(types ...string) SlogOption {
+	return func(s *slogPlugin) {
+		for _, t := range types {
+			s.redacted[t] = struct{}{}
+		}
+	}
+}
+
+// WithSlogOmittedTypes doesn't log values of types.
+// Types are written as in WithSlogRedactedTypes, for example []byte.
+func WithSlogOmittedTypes(types ...string) SlogOption {
+	return func(s *slogPlugin) {
+		for _, t := range types {
+			s.omitted[t] = struct{}{}
+		}
+	}
+}
+
+// WithSlogContextLogger pulls a logger from a context instead of a logger input.
+// A function with a name funcName from a package importPath must have a signature
+// func(context.Context) *slog.Logger. A name of the package must match the last element of importPath.
+func WithSlogContextLogger(importPath, funcName string) SlogOption {
+	return func(s *slogPlugin) {
+		s.fromContextImport = importPath
+		s.fromContextFunc = funcName
+	}
+}
+
+type slogPlugin struct {
+	redacted map[string]struct{}
+	omitted  map[string]struct{}
+
+	fromContextImport string
+	fromContextFunc   string
+}
+
+func (s *slogPlugin) buildLoggerType() ast.Expr {
+	return &ast.StarExpr{
+		X: &ast.SelectorExpr{
+			X:   ast.NewIdent("slog"),
+			Sel: ast.NewIdent("Logger"),
+		},
+	}
+}
+
+func (s *slogPlugin) buildContextType() ast.Expr {
+	return &ast.SelectorExpr{
+		X:   ast.NewIdent("context"),
+		Sel: ast.NewIdent("Context"),
+	}
+}
+
+// buildLogger returns an injected logger or a logger from a context
+func (s *slogPlugin) buildLogger(ctx plugin.Context) ast.Expr {
+	if s.fromContextFunc == "" {
+		lVariable := ctx.FindInputByType(s.buildLoggerType())
+		if lVariable == nil {
+			lVariable = ctx.AddInput(s.buildLoggerType())
+		}
+		return lVariable.Names[0]
+	}
+
+	ctxVariable := ctx.FindInputByType(s.buildContextType())
+	if ctxVariable == nil {
+		ctxVariable = ctx.AddInput(s.buildContextType())
+	}
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   ast.NewIdent(path.Base(s.fromContextImport)),
+			Sel: ast.NewIdent(s.fromContextFunc),
+		},
+		Args: []ast.Expr{ctxVariable.Names[0]},
+	}
+}
+
+// stepInfo returns types of the flow and parameters and results of a component as they are declared.
+// Results are nil if a context doesn't have information about a component.
+func (s *slogPlugin) stepInfo(ctx plugin.Context) (*types.Info, plugin.DeclaredFieldsStmt) {
+	stepCtx, ok := ctx.(plugin.StepContext)
+	if !ok {
+		return nil, nil
+	}
+	declared, _ := stepCtx.Step().(plugin.DeclaredFieldsStmt)
+	return stepCtx.TypesInfo(), declared
+}
+
+// hasType checks that a type is in a set of types. Types are matched by a name with
+// a package name and by a type key, so aliases of types and imports are matched too.
+func (s *slogPlugin) hasType(set map[string]struct{}, info *types.Info, t ast.Expr) bool {
+	for _, name := range []string{fields.GetTypeName(info, t), fields.GetTypeKey(info, t)} {
+		if _, ok := set[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// attrKey returns a declared name of a parameter or a result with an index.
+// A name of a type is used for unnamed fields.
+func (s *slogPlugin) attrKey(info *types.Info, declared []*ast.Field, index int, field *ast.Field) string {
+	if index < len(declared) && len(declared[index].Names) > 0 {
+		if name := declared[index].Names[0].Name; name != "" && name != "_" {
+			return name
+		}
+	}
+	return fields.GetTypeName(info, field.Type)
+}
+
+// buildAttrs returns key/value pairs with a flow name, a step name and variables.
+// Variables are in the same order as declared fields.
+func (s *slogPlugin) buildAttrs(ctx plugin.Context, name string, vars, declared []*ast.Field) []ast.Expr {
+	info, _ := s.stepInfo(ctx)
+	attrs := []ast.Expr{
+		&ast.BasicLit{Kind: token.STRING, Value: `"flow"`},
+		&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", plugin.FlowName(ctx))},
+		&ast.BasicLit{Kind: token.STRING, Value: `"step"`},
+		&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", name)},
+	}
+	for index, field := range vars {
+		if len(field.Names) == 0 || field.Names[0] == nil {
+			continue
+		}
+		if s.hasType(s.omitted, info, field.Type) {
+			continue
+		}
+		var value ast.Expr = field.Names[0]
+		if s.hasType(s.redacted, info, field.Type) {
+			value = &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", SlogRedactedValue)}
+		}
+		key := s.attrKey(info, declared, index, field)
+		attrs = append(attrs, &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", key)}, value)
+	}
+	return attrs
+}
+
+func (s *slogPlugin) buildLogStmt(logger ast.Expr, level, msg string, attrs []ast.Expr) ast.Stmt {
+	return &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   logger,
+				Sel: ast.NewIdent(level),
+			},
+			Args: append([]ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", msg)}}, attrs...),
+		},
+	}
+}
+
+func (s *slogPlugin) Before(ctx plugin.Context, name string, inputFields []*ast.Field) []ast.Stmt {
+	var declared []*ast.Field
+	if _, step := s.stepInfo(ctx); step != nil {
+		declared = step.DeclaredInputFields()
+	}
+	return []ast.Stmt{
+		s.buildLogStmt(s.buildLogger(ctx), "Info", "step started", s.buildAttrs(ctx, name, inputFields, declared)),
+	}
+}
+
+// Change logs an error before failure statements of the component.
+func (s *slogPlugin) Change(ctx plugin.Context, c plugin.ComponentStmt) bool {
+	if c.ErrStmt() == nil {
+		return false
+	}
+
+	var changed bool
+	for _, stmt := range c.ErrStmt().List {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+		if !ok {
+			continue
+		}
+		errVar, ok := cond.X.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		attrs := append(s.buildAttrs(ctx, c.Name(), nil, nil), &ast.BasicLit{Kind: token.STRING, Value: `"error"`}, errVar)
+		ifStmt.Body.List = append([]ast.Stmt{s.buildLogStmt(s.buildLogger(ctx), "Error", "step failed", attrs)}, ifStmt.Body.List...)
+		changed = true
+	}
+	return changed
+}
+
+// Success logs outputs of the component. An error isn't logged, because it's nil.
+func (s *slogPlugin) Success(ctx plugin.Context, name string, outputFields []*ast.Field) []ast.Stmt {
+	info, step := s.stepInfo(ctx)
+	var declaredFields []*ast.Field
+	if step != nil {
+		declaredFields = step.DeclaredOutputFields()
+	}
+
+	outputs := make([]*ast.Field, 0, len(outputFields))
+	declared := make([]*ast.Field, 0, len(outputFields))
+	for index, field := range outputFields {
+		if fields.GetTypeKey(info, field.Type) == "error" {
+			continue
+		}
+		outputs = append(outputs, field)
+		if index < len(declaredFields) {
+			declared = append(declared, declaredFields[index])
+		} else {
+			declared = append(declared, &ast.Field{Type: field.Type})
+		}
+	}
+	return []ast.Stmt{
+		s.buildLogStmt(s.buildLogger(ctx), "Info", "step succeeded", s.buildAttrs(ctx, name, outputs, declared)),
+	}
+}
+
+func (s *slogPlugin) Imports() []string {
+	if s.fromContextFunc != "" {
+		return []string{
+			"context",
+			s.fromContextImport,
+		}
+	}
+	return []string{
+		slogLibrary,
+	}
+}
+
+// Initializes structured logging plugin. The plugin logs a start, a success and a failure
+// of every component with a flow name, a step name, inputs and outputs as attributes.
+// Attributes are named as parameters and results of steps, unnamed results are named by types.
+// Values of context.Context and *slog.Logger aren't logged.
+//
+// Example of generated code:
+//
+//      func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+//          return func(loggerPtrVal *slog.Logger, id string) error {
+//              loggerPtrVal.Info("step started", "flow", "BuildComponent2", "step", "Step1", "id", id)
+//              err := service.Step1(id)
+//              if err != nil {
+//                  loggerPtrVal.Error("step failed", "flow", "BuildComponent2", "step", "Step1", "error", err)
+//                  return err
+//              }
+//              loggerPtrVal.Info("step succeeded", "flow", "BuildComponent2", "step", "Step1")
+//              return nil
+//          }
+//      }
+func NewSlogPlugin(opts ...SlogOption) plugin.Plugin {
+	s := &slogPlugin{
+		redacted: make(map[string]struct{}),
+		omitted: map[string]struct{}{
+			"context.Context": {},
+			"*slog.Logger":    {},
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
@@ -3,24 +3,51 @@ package plugins
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 
 	"github.com/GettEngineering/effe/fields"
 	"github.com/GettEngineering/effe/plugin"
 )
 
+// WrapErrorMode declares how the wrap error plugin wraps errors of steps
+type WrapErrorMode int
+
+const (
+	// PkgErrorsMode wraps errors with github.com/pkg/errors
+	PkgErrorsMode WrapErrorMode = iota
+	// StdlibMode wraps errors with fmt.Errorf and %w
+	StdlibMode
+	// StepErrorMode wraps errors with *effe.StepError
+	StepErrorMode
+)
+
+// WrapErrorOption configures the wrap error plugin
+type WrapErrorOption func(*wrapError)
+
+// WithWrapErrorMode sets a mode of wrapping errors. Default mode is PkgErrorsMode.
+func WithWrapErrorMode(mode WrapErrorMode) WrapErrorOption {
+	return func(w *wrapError) {
+		w.mode = mode
+	}
+}
+
 type wrapError struct {
 	plugin.Plugin
+
+	mode WrapErrorMode
+	// imports are imports used by the last component
+	imports map[string]struct{}
 }
 
-func (w wrapError) Before(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+func (w *wrapError) Before(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
 	return []ast.Stmt{}
 }
 
-func (w wrapError) Success(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+func (w *wrapError) Success(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
 	return []ast.Stmt{}
 }
 
-func (w wrapError) findAllReturnStmts(block *ast.BlockStmt) []*ast.ReturnStmt {
+func (w *wrapError) findAllReturnStmts(block *ast.BlockStmt) []*ast.ReturnStmt {
 	var returnStmts []*ast.ReturnStmt
 
 	for _, stmt := range block.List {
@@ -48,7 +75,8 @@ func (w wrapError) findAllReturnStmts(block *ast.BlockStmt) []*ast.ReturnStmt {
 	return returnStmts
 }
 
-func (w wrapError) Change(ctx plugin.Context, componentStmt plugin.ComponentStmt) bool {
+func (w *wrapError) Change(ctx plugin.Context, componentStmt plugin.ComponentStmt) bool {
+	w.imports = make(map[string]struct{})
 	if componentStmt.ErrStmt() == nil {
 		return false
 	}
@@ -67,51 +95,124 @@ func (w wrapError) Change(ctx plugin.Context, componentStmt plugin.ComponentStmt
 
 	for _, returnStmt := range returnStmts {
 		for index := range errIndexes {
-			var (
-				sel  *ast.Ident
-				args []ast.Expr
-			)
-
-			msg := &ast.BasicLit{
-				Value: fmt.Sprintf("\"failure call %s\"", componentStmt.Name()),
-			}
-
 			field := returnStmt.Results[index]
 
-			if fields.GetTypeStrName(field) == "nil" {
-				sel = ast.NewIdent("New")
-				args = append(args, msg)
-			} else {
-				sel = ast.NewIdent("Wrap")
-
-				args = append(args, field)
-				args = append(args, msg)
+			switch w.mode {
+			case StdlibMode:
+				returnStmt.Results[index] = w.buildStdlibError(componentStmt.Name(), field)
+			case StepErrorMode:
+				returnStmt.Results[index] = w.buildStepError(plugin.FlowName(ctx), componentStmt.Name(), field)
+			default:
+				returnStmt.Results[index] = w.buildPkgError(componentStmt.Name(), field)
 			}
 
 			if !pluginUsed {
 				pluginUsed = true
 			}
-
-			returnStmt.Results[index] = &ast.CallExpr{
-				Fun: &ast.SelectorExpr{
-					X:   ast.NewIdent("errors"),
-					Sel: sel,
-				},
-				Args: args,
-			}
 		}
 	}
 
 	return pluginUsed
 }
 
-func (w wrapError) Imports() []string {
-	return []string{
-		"github.com/pkg/errors",
+func (w *wrapError) buildFailureMsg(name string, format string) *ast.BasicLit {
+	return &ast.BasicLit{
+		Value: fmt.Sprintf("\"failure call %s%s\"", name, format),
+	}
+}
+
+func (w *wrapError) buildPkgError(name string, field ast.Expr) ast.Expr {
+	var (
+		sel  *ast.Ident
+		args []ast.Expr
+	)
+
+	msg := w.buildFailureMsg(name, "")
+
+	if fields.GetTypeStrName(field) == "nil" {
+		sel = ast.NewIdent("New")
+		args = append(args, msg)
+	} else {
+		sel = ast.NewIdent("Wrap")
+
+		args = append(args, field)
+		args = append(args, msg)
+	}
+
+	w.imports["github.com/pkg/errors"] = struct{}{}
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   ast.NewIdent("errors"),
+			Sel: sel,
+		},
+		Args: args,
+	}
+}
+
+func (w *wrapError) buildStdlibError(name string, field ast.Expr) ast.Expr {
+	if fields.GetTypeStrName(field) == "nil" {
+		w.imports["errors"] = struct{}{}
+		return &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   ast.NewIdent("errors"),
+				Sel: ast.NewIdent("New"),
+			},
+			Args: []ast.Expr{w.buildFailureMsg(name, "")},
+		}
+	}
+
+	w.imports["fmt"] = struct{}{}
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   ast.NewIdent("fmt"),
+			Sel: ast.NewIdent("Errorf"),
+		},
+		Args: []ast.Expr{w.buildFailureMsg(name, ": %w"), field},
+	}
+}
+
+func (w *wrapError) buildStepError(flowName, name string, field ast.Expr) ast.Expr {
+	elts := []ast.Expr{
+		&ast.KeyValueExpr{
+			Key:   ast.NewIdent("Flow"),
+			Value: &ast.BasicLit{Value: fmt.Sprintf("%q", flowName)},
+		},
+		&ast.KeyValueExpr{
+			Key:   ast.NewIdent("Step"),
+			Value: &ast.BasicLit{Value: fmt.Sprintf("%q", name)},
+		},
+	}
+	if fields.GetTypeStrName(field) != "nil" {
+		elts = append(elts, &ast.KeyValueExpr{
+			Key:   ast.NewIdent("Err"),
+			Value: field,
+		})
+	}
+
+	w.imports["github.com/GettEngineering/effe"] = struct{}{}
+	return &ast.UnaryExpr{
+		Op: token.AND,
+		X: &ast.CompositeLit{
+			Type: &ast.SelectorExpr{
+				X:   ast.NewIdent("effe"),
+				Sel: ast.NewIdent("StepError"),
+			},
+			Elts: elts,
+		},
+	}
+}
+
+// Imports returns imports used by the last component, they depend on a mode of the plugin.
+func (w *wrapError) Imports() []string {
+	imports := make([]string, 0, len(w.imports))
+	for impr := range w.imports {
+		imports = append(imports, impr)
 	}
+	return imports
 }
 
-// Initializes WrapPlugin
+// Initializes WrapPlugin. Errors are wrapped with github.com/pkg/errors by default,
+// a mode can be changed with WithWrapErrorMode.
 //
 // Example:
 //
@@ -127,6 +228,24 @@ func (w wrapError) Imports() []string {
 //          }
 //      }
 //  }
-func NewWrapError() plugin.Plugin {
-	return &wrapError{}
+//
+// Example of StdlibMode:
+//
+//  err := service.Step1()
+//  if err != nil {
+//      return fmt.Errorf("failure call Step1: %w", err)
+//  }
+//
+// Example of StepErrorMode:
+//
+//  err := service.Step1()
+//  if err != nil {
+//      return &effe.StepError{Flow: "BuildComponent2", Step: "Step1", Err: err}
+//  }
+func NewWrapError(opts ...WrapErrorOption) plugin.Plugin {
+	w := &wrapError{}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
 }
@@ -2,8 +2,10 @@ package strategies
 
 import (
 	"go/ast"
+	goTypes "go/types"
 
 	"github.com/GettEngineering/effe/fields"
+	"github.com/GettEngineering/effe/plugin"
 )
 
 type VarBuilder func(t ast.Expr) *ast.Ident
@@ -21,28 +23,82 @@ type BlockContext struct {
 	Output  *ast.FieldList
 	Vars    map[string]*ast.Ident
 	Builder VarBuilder
+	// TypesInfo is used for matching types of variables. Variables are matched
+	// by string representations of types if TypesInfo is nil.
+	TypesInfo *goTypes.Info
+	// Flow is a name of the generated flow, plugins use it for describing components.
+	Flow string
+	// valueNames contains names of values which are bound to fields of calls in the block,
+	// input and output of the block.
+	valueNames valueNames
+}
+
+// values returns names of values which are bound to fields
+func (b *BlockContext) values() valueNames {
+	if b.valueNames == nil {
+		b.valueNames = make(valueNames)
+	}
+	return b.valueNames
+}
+
+// ValueName returns a name of a value which is bound to a field of input or output of the block
+// or to a field of a call in the block. It returns an empty string for unnamed values.
+func (b *BlockContext) ValueName(f *ast.Field) string {
+	return b.valueNames[f]
+}
+
+// valueKey returns a key of a value which is bound to a field
+func (b *BlockContext) valueKey(f *ast.Field) string {
+	return fields.GetValueKey(b.TypesInfo, b.ValueName(f), f.Type)
+}
+
+// FlowName returns a name of the generated flow
+func (b BlockContext) FlowName() string {
+	return b.Flow
+}
+
+// NewVar returns a new variable for a type, the variable has a unique name in the generated flow.
+func (b *BlockContext) NewVar(t ast.Expr) *ast.Ident {
+	return b.Builder(t)
+}
+
+// stepContext is a context which is passed to hooks of a component
+type stepContext struct {
+	*BlockContext
+	step ComponentStmt
+}
+
+// Step returns a component statement for which hooks are called
+func (s stepContext) Step() plugin.ComponentStmt {
+	return s.step
+}
+
+// TypesInfo returns types of expressions of a package with the flow
+func (s stepContext) TypesInfo() *goTypes.Info {
+	return s.BlockContext.TypesInfo
 }
 
 //nolint:gocognit
 func (b *BlockContext) CalculateInput(calls []ComponentCall) {
 	for index, c := range calls {
+		b.values().bindCall(c)
 		if c.Input() == nil {
 			continue
 		}
 		for _, inputField := range c.Input().List {
 			var foundSourceOfArg bool
 			for _, previous := range calls[:index] {
-				if previous.Output() != nil && fields.FindFieldWithType(previous.Output().List, inputField.Type) != nil {
+				if previous.Output() != nil && b.values().findSourceOfArg(b.TypesInfo, previous.Output().List, inputField) != nil {
 					foundSourceOfArg = true
 					break
 				}
 			}
 
 			if !foundSourceOfArg {
-				existsInInput := fields.FindFieldWithType(b.Input.List, inputField.Type)
-				ident, _ := b.genVariable(inputField.Type)
+				existsInInput := b.values().findField(b.TypesInfo, b.Input.List, inputField)
+				ident, _ := b.genFieldVariable(inputField)
 				if existsInInput == nil {
-					b.addInput(ident, inputField.Type)
+					b.addInput(ident, inputField)
 				} else {
 					existsInInput.Names = []*ast.Ident{ident}
 				}
@@ -51,6 +107,41 @@ func (b *BlockContext) CalculateInput(calls []ComponentCall) {
 	}
 }
 
+// key returns a key of a value which is bound to a field
+func (v valueNames) key(info *goTypes.Info, f *ast.Field) string {
+	return fields.GetValueKey(info, v[f], f.Type)
+}
+
+// findField searches a field bound to the same value in an array. Returns a field or nil.
+func (v valueNames) findField(info *goTypes.Info, list []*ast.Field, field *ast.Field) *ast.Field {
+	key := v.key(info, field)
+	for _, f := range list {
+		if v.key(info, f) == key {
+			return f
+		}
+	}
+	return nil
+}
+
+// findSourceOfArg searches an output which is passed to an argument.
+// An argument bound to a named value takes the named value or an unnamed value with the same type.
+func (v valueNames) findSourceOfArg(info *goTypes.Info, outputs []*ast.Field, inputField *ast.Field) *ast.Field {
+	if output := v.findField(info, outputs, inputField); output != nil {
+		return output
+	}
+	return fields.FindFieldWithTypeInfo(info, outputs, inputField.Type)
+}
+
+// usesOutput checks that an output is passed to one of arguments.
+func (v valueNames) usesOutput(info *goTypes.Info, input []*ast.Field, output *ast.Field) bool {
+	for _, inputField := range input {
+		if v.findSourceOfArg(info, []*ast.Field{output}, inputField) != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *BlockContext) OutputList() []*ast.Field {
 	return b.Output.List
 }
@@ -58,6 +149,7 @@ func (b *BlockContext) OutputList() []*ast.Field {
 //nolint:gocognit
 func (b *BlockContext) CalculateOutput(calls []ComponentCall) {
 	for index, c := range calls {
+		b.values().bindCall(c)
 		if c.Output() == nil {
 			continue
 		}
@@ -68,33 +160,44 @@ func (b *BlockContext) CalculateOutput(calls []ComponentCall) {
 				if next.Input() == nil {
 					continue
 				}
-				if next.Input() != nil && fields.FindFieldWithType(next.Input().List, outputField.Type) != nil {
+				if next.Input() != nil && b.values().usesOutput(b.TypesInfo, next.Input().List, outputField) {
 					foundUsageOfOutput = true
 					break
 				}
 			}
 
-			if !foundUsageOfOutput && fields.FindFieldWithType(b.Output.List, outputField.Type) == nil {
-				b.addOutput(outputField.Type)
+			if !foundUsageOfOutput && b.values().findField(b.TypesInfo, b.Output.List, outputField) == nil {
+				b.addOutput(outputField)
 			}
 		}
 	}
 }
-func (b *BlockContext) addInput(name *ast.Ident, t ast.Expr) {
-	b.Input.List = append(b.Input.List, &ast.Field{
+func (b *BlockContext) addInput(name *ast.Ident, inputField *ast.Field) {
+	field := &ast.Field{
 		Names: []*ast.Ident{name},
-		Type:  t,
-	})
+		Type:  inputField.Type,
+	}
+	b.bindField(field, inputField)
+	b.Input.List = append(b.Input.List, field)
 }
 
-func (b *BlockContext) addOutput(t ast.Expr) {
-	b.Output.List = append(b.Output.List, &ast.Field{
-		Type: t,
-	})
+func (b *BlockContext) addOutput(outputField *ast.Field) {
+	field := &ast.Field{
+		Type: outputField.Type,
+	}
+	b.bindField(field, outputField)
+	b.Output.List = append(b.Output.List, field)
+}
+
+// bindField binds a field to a value which is bound to a source field
+func (b *BlockContext) bindField(field, source *ast.Field) {
+	if name := b.ValueName(source); name != "" {
+		b.values()[field] = name
+	}
 }
 
 func (b BlockContext) FindInputByType(t ast.Expr) *ast.Field {
-	return fields.FindFieldWithType(b.Input.List, t)
+	return fields.FindFieldWithTypeInfo(b.TypesInfo, b.Input.List, t)
 }
 
 func (b *BlockContext) AddInput(t ast.Expr) *ast.Field {
@@ -109,26 +212,45 @@ func (b *BlockContext) AddInput(t ast.Expr) *ast.Field {
 		Type:  t,
 	}
 	b.Input.List = append(b.Input.List, field)
-	b.Vars[fields.GetTypeStrName(t)] = v
+	b.Vars[fields.GetTypeKey(b.TypesInfo, t)] = v
 	return field
 }
 
 func (b *BlockContext) genVariable(t ast.Expr) (*ast.Ident, bool) {
-	typeStr := fields.GetTypeStrName(t)
-	v, ok := b.Vars[typeStr]
+	return b.genFieldVariable(&ast.Field{Type: t})
+}
+
+// genFieldVariable returns a variable for a value which is bound to a field.
+// Named values have own variables, so they don't overwrite unnamed values with the same type.
+func (b *BlockContext) genFieldVariable(f *ast.Field) (*ast.Ident, bool) {
+	key := b.valueKey(f)
+	v, ok := b.Vars[key]
 	if !ok {
-		v = b.Builder(t)
-		b.Vars[typeStr] = v
+		v = b.Builder(f.Type)
+		b.Vars[key] = v
 	}
 	return v, ok
 }
 
+// findArgVariable searches a variable which is passed to an argument.
+// An argument bound to a named value takes the named value or an unnamed value with the same type.
+func (b *BlockContext) findArgVariable(inputField *ast.Field) (*ast.Ident, bool) {
+	if v, ok := b.Vars[b.valueKey(inputField)]; ok {
+		return v, true
+	}
+	v, ok := b.Vars[fields.GetTypeKey(b.TypesInfo, inputField.Type)]
+	return v, ok
+}
+
 func (b *BlockContext) BuildInputVars(input *ast.FieldList) []*ast.Field {
 	args := make([]*ast.Field, len(input.List))
 	for index, inputField := range input.List {
-		v, ok := b.genVariable(inputField.Type)
-		if !ok && fields.FindFieldWithType(b.Input.List, inputField.Type) == nil {
-			b.addInput(v, inputField.Type)
+		v, ok := b.findArgVariable(inputField)
+		if !ok {
+			v, _ = b.genFieldVariable(inputField)
+			if b.values().findField(b.TypesInfo, b.Input.List, inputField) == nil {
+				b.addInput(v, inputField)
+			}
 		}
 		args[index] = &ast.Field{
 			Type:  inputField.Type,
@@ -143,7 +265,7 @@ func (b *BlockContext) BuildOutputVars(output *ast.FieldList) ([]*ast.Field, boo
 
 	allOutputVarsExist := true
 	for index, outputField := range output.List {
-		v, ok := b.genVariable(outputField.Type)
+		v, ok := b.genFieldVariable(outputField)
 		vars[index] = &ast.Field{
 			Type:  outputField.Type,
 			Names: []*ast.Ident{v},
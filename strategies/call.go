@@ -9,11 +9,60 @@ type ComponentCall interface {
 	Name() *ast.Ident
 }
 
+// CompensatedCall is implemented by calls of steps which declare a compensation.
+type CompensatedCall interface {
+	Compensation() ComponentCall
+}
+
+// NamedValuesCall is implemented by calls which bind arguments or results to named values declared with effe.As.
+type NamedValuesCall interface {
+	// ValueName returns a name of a value which is bound to a field of Input() or Output().
+	// It returns an empty string for unnamed values.
+	ValueName(*ast.Field) string
+}
+
+// getValueName returns a name of a value which is bound to a field of a call
+func getValueName(c ComponentCall, f *ast.Field) string {
+	if namedCall, ok := c.(NamedValuesCall); ok {
+		return namedCall.ValueName(f)
+	}
+	return ""
+}
+
+// deferredCall is a call which is deferred until a block returns.
+type deferredCall struct {
+	ComponentCall
+}
+
+func (d deferredCall) ValueName(f *ast.Field) string {
+	return getValueName(d.ComponentCall, f)
+}
+
+// valueNames keeps names of values which are bound to fields of calls
+type valueNames map[*ast.Field]string
+
+// bindCall adds names of values which are bound to arguments and results of a call
+func (v valueNames) bindCall(c ComponentCall) {
+	for _, list := range []*ast.FieldList{c.Input(), c.Output()} {
+		if list == nil {
+			continue
+		}
+		for _, f := range list.List {
+			if name := getValueName(c, f); name != "" {
+				v[f] = name
+			}
+		}
+	}
+}
+
 type componentCall struct {
-	fn     ast.Expr
-	input  *ast.FieldList
-	output *ast.FieldList
-	name   *ast.Ident
+	fn           ast.Expr
+	input        *ast.FieldList
+	output       *ast.FieldList
+	name         *ast.Ident
+	compensation ComponentCall
+	// valueNames contains names of values which are bound to fields of input and output
+	valueNames valueNames
 }
 
 func (c componentCall) Fn() ast.Expr {
@@ -31,3 +80,11 @@ func (c componentCall) Output() *ast.FieldList {
 func (c componentCall) Name() *ast.Ident {
 	return c.name
 }
+
+func (c componentCall) Compensation() ComponentCall {
+	return c.compensation
+}
+
+func (c componentCall) ValueName(f *ast.Field) string {
+	return c.valueNames[f]
+}
@@ -15,7 +15,9 @@ type Chain interface {
 }
 
 type chain struct {
-	plugins           []plugin.Plugin
+	plugins           []scopedPlugin
+	flowName          string
+	directives        []string
 	serviceObjectName string
 	generators        map[string]Generator
 }
@@ -28,9 +30,20 @@ func WithServiceObjectName(newServiceObjectName string) Option {
 	}
 }
 
-func Use(p plugin.Plugin) Option {
+// Use adds a plugin to a chain. The plugin is applied to components which are selected
+// by all selectors, without selectors the plugin is applied to all components.
+//
+// Hooks of plugin.FlowPlugin are applied with a scope of a flow, which doesn't have a step
+// and a kind. So ForSteps and ForKinds disable flow hooks of the plugin, for example
+// an audit of flows must be selected only by ForFlows, ForDirective or Not(ForSteps(...)).
+//
+// Example:
+//
+//	strategies.Use(plugins.NewSlogPlugin(), strategies.ForDirective("payments"))
+//	strategies.Use(plugins.NewLogPlugin(), strategies.Not(strategies.ForSteps("GetRate")))
+func Use(p plugin.Plugin, selectors ...Selector) Option {
 	return func(c *chain) {
-		c.plugins = append(c.plugins, p)
+		c.plugins = append(c.plugins, scopedPlugin{plugin: p, selectors: selectors})
 	}
 }
 
@@ -42,6 +55,11 @@ func Default() map[string]Generator {
 		"SimpleComponent":   GenSimpleComponentCall,
 		"CaseComponent":     GenCaseComponentCall,
 		"WrapComponent":     GenWrapComponentCall,
+		"ParallelComponent": GenParallelComponentCall,
+		"RetryComponent":    GenRetryComponentCall,
+		"TimeoutComponent":  GenTimeoutComponentCall,
+		"ForEachComponent":  GenForEachComponentCall,
+		"IfComponent":       GenIfComponentCall,
 	}
 }
 
@@ -55,8 +73,26 @@ func (c *chain) Register(t string, gen Generator) error {
 	return nil
 }
 
+// SetFlowName sets a name of a flow, which is built by the next call of BuildFlow.
+// Plugins use the name for describing components, hooks of plugin.FlowPlugin
+// are applied only to flows with names.
+func (c *chain) SetFlowName(flowName string) {
+	c.flowName = flowName
+}
+
+// SetFlowDirectives sets directives of a flow, which is built by the next call of BuildFlow.
+// Directives are used by selectors of plugins.
+func (c *chain) SetFlowDirectives(directives []string) {
+	c.directives = directives
+}
+
 func (c *chain) BuildFlow(components []types.Component, failure types.Component, typesInfo *goTypes.Info) (ast.Expr, []string, error) {
+	flowName, directives := c.flowName, c.directives
+	c.flowName, c.directives = "", nil
 	f := &flowGen{
+		flowName:              flowName,
+		directives:            directives,
+		componentKinds:        make(map[ast.Expr]string),
 		globalVarNamesCounter: make(map[string]int),
 		importSet:             make(map[string]struct{}),
 		chain:                 c,
@@ -81,6 +117,16 @@ func (c *chain) BuildFlow(components []types.Component, failure types.Component,
 		call = BuildMultiComponentCall(f, calls, nil)
 	}
 
+	if f.compensations != nil {
+		call, err = f.buildCompensatedFlowCall(call)
+		if err != nil {
+			return nil, imports, err
+		}
+	}
+
+	// Hooks are applied after compensations, so they see an error which is returned by the flow
+	f.applyFlowHooks(call)
+
 	for impr := range f.importSet {
 		imports = append(imports, impr)
 	}
@@ -90,7 +136,7 @@ func (c *chain) BuildFlow(components []types.Component, failure types.Component,
 
 func NewChain(opts ...Option) Chain {
 	c := &chain{
-		plugins:           make([]plugin.Plugin, 0),
+		plugins:           make([]scopedPlugin, 0),
 		serviceObjectName: "service",
 		generators:        Default(),
 	}
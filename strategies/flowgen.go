@@ -12,21 +12,28 @@ import (
 	"github.com/GettEngineering/effe/fields"
 	"github.com/GettEngineering/effe/plugin"
 	"github.com/GettEngineering/effe/types"
+	"github.com/pkg/errors"
 )
 
 const (
-	errorExpr  = "error"
-	fmtLibrary = "fmt"
+	errorExpr      = "error"
+	fmtLibrary     = "fmt"
+	syncLibrary    = "sync"
+	contextLibrary = "context"
+	timeLibrary    = "time"
+	effeLibrary    = "github.com/GettEngineering/effe"
 )
 
 type FlowGen interface {
 	AddImport(string)
 	BuildComponentStmt(ctx *BlockContext, cCall, failureCall ComponentCall) ComponentStmt
+	BuildCompensationStmt(ctx *BlockContext, compensationCall ComponentCall) ast.Stmt
 	ApplyPlugins(ctx *BlockContext, componentStmt ComponentStmt) *ast.BlockStmt
 	ServiceName() string
 	VarBuilder() VarBuilder
 	GenComponentCall(types.Component) (ComponentCall, error)
 	TypesInfo() *goTypes.Info
+	FlowName() string
 }
 
 func (f flowGen) ServiceName() string {
@@ -34,18 +41,31 @@ func (f flowGen) ServiceName() string {
 }
 
 type flowGen struct {
+	flowName              string
+	directives            []string
 	globalVarNamesCounter map[string]int
-	plugins               []plugin.Plugin
+	plugins               []scopedPlugin
+	componentKinds        map[ast.Expr]string
 	importSet             map[string]struct{}
 	serviceObjectName     string
 	chain                 *chain
 	typesInfo             *goTypes.Info
+
+	// compensations is a variable with type *effe.Compensations declared in the flow
+	compensations *ast.Ident
+	// compensationCtx is a context of the flow which is passed to compensations
+	compensationCtx *ast.Ident
 }
 
 func (f flowGen) TypesInfo() *goTypes.Info {
 	return f.typesInfo
 }
 
+// FlowName returns a name of the generated flow
+func (f flowGen) FlowName() string {
+	return f.flowName
+}
+
 func (f *flowGen) GenComponentCall(component types.Component) (ComponentCall, error) {
 	cType := reflect.TypeOf(component).String()
 	dotIndex := strings.Index(cType, ".")
@@ -54,7 +74,29 @@ func (f *flowGen) GenComponentCall(component types.Component) (ComponentCall, er
 		cType = string([]byte(cType)[dotIndex+1:])
 	}
 
-	return f.chain.generators[cType](f, component)
+	call, err := f.chain.generators[cType](f, component)
+	if err == nil && call != nil {
+		f.componentKinds[call.Fn()] = cType
+	}
+	return call, err
+}
+
+// pluginsFor returns plugins which are applied to a component of the flow.
+// Flow hooks are applied with an empty name and an empty kind of a component.
+func (f *flowGen) pluginsFor(step, kind string) []plugin.Plugin {
+	scope := Scope{
+		Flow:       f.flowName,
+		Directives: f.directives,
+		Step:       step,
+		Kind:       kind,
+	}
+	plugins := make([]plugin.Plugin, 0, len(f.plugins))
+	for _, p := range f.plugins {
+		if p.match(scope) {
+			plugins = append(plugins, p.plugin)
+		}
+	}
+	return plugins
 }
 
 func (f *flowGen) AddImport(impr string) {
@@ -104,7 +146,7 @@ func (f *flowGen) buildFailureBlock(ctx *BlockContext, failureCall ComponentCall
 		}
 
 		failMsg := fmt.Sprintf("failure call %s", cName)
-		returnStmt, usedFmtLibrary := BuildFailureReturnStmt(ctx.Output, ctx.Vars, failMsg, f.typesInfo)
+		returnStmt, usedFmtLibrary := buildFailureReturnStmt(ctx.Output, ctx.Vars, failMsg, f.typesInfo, ctx.ValueName)
 
 		if usedFmtLibrary {
 			f.AddImport(fmtLibrary)
@@ -134,9 +176,12 @@ func (f *flowGen) BuildComponentStmt(ctx *BlockContext, cCall, failureCall Compo
 	}
 	componentStmt := &componentStmt{
 		componentName: name,
+		kind:          f.componentKinds[cCall.Fn()],
 	}
 
+	ctx.values().bindCall(cCall)
 	componentStmt.inputFields = ctx.BuildInputVars(cCall.Input())
+	componentStmt.declaredInput = cCall.Input().List
 
 	call := &ast.CallExpr{
 		Fun:  cCall.Fn(),
@@ -160,6 +205,7 @@ func (f *flowGen) BuildComponentStmt(ctx *BlockContext, cCall, failureCall Compo
 		}
 
 		componentStmt.outputFields = outputFields
+		componentStmt.declaredOutput = cCall.Output().List
 		componentStmt.stmt = assignStmt
 	} else {
 		componentStmt.stmt = &ast.ExprStmt{
@@ -174,22 +220,30 @@ func (f *flowGen) BuildComponentStmt(ctx *BlockContext, cCall, failureCall Compo
 func (f *flowGen) ApplyPlugins(ctx *BlockContext, componentStmt ComponentStmt) *ast.BlockStmt {
 	usedPlugins := make(map[plugin.Plugin]struct{})
 	block := &ast.BlockStmt{}
+	plugins := f.pluginsFor(componentStmt.Name(), componentKind(componentStmt))
+	stepCtx := stepContext{BlockContext: ctx, step: componentStmt}
 
-	for _, p := range f.plugins {
-		beforeStmts := p.Before(ctx, componentStmt.Name(), componentStmt.InputFields())
+	for _, p := range plugins {
+		beforeStmts := p.Before(stepCtx, componentStmt.Name(), componentStmt.InputFields())
 		block.List = append(block.List, beforeStmts...)
-		pluginUsed := p.Change(ctx, componentStmt)
+		pluginUsed := p.Change(stepCtx, componentStmt)
 		if len(beforeStmts) > 0 || pluginUsed {
 			usedPlugins[p] = struct{}{}
 		}
 	}
 
+	if componentStmt.ErrStmt() != nil {
+		for p := range f.applyFailureHooks(stepCtx, componentStmt, plugins) {
+			usedPlugins[p] = struct{}{}
+		}
+	}
+
 	block.List = append(block.List, componentStmt.Stmt())
 	if componentStmt.ErrStmt() != nil {
 		block.List = append(block.List, componentStmt.ErrStmt().List...)
 	}
-	for _, p := range f.plugins {
-		afterStmts := p.Success(ctx, componentStmt.Name(), componentStmt.OutputFields())
+	for _, p := range plugins {
+		afterStmts := p.Success(stepCtx, componentStmt.Name(), componentStmt.OutputFields())
 		if len(afterStmts) > 0 {
 			usedPlugins[p] = struct{}{}
 		}
@@ -204,3 +258,311 @@ func (f *flowGen) ApplyPlugins(ctx *BlockContext, componentStmt ComponentStmt) *
 
 	return block
 }
+
+// applyFailureHooks adds statements of failure plugins to failure blocks of a component.
+// Returns plugins which added statements.
+func (f *flowGen) applyFailureHooks(ctx plugin.Context, componentStmt ComponentStmt, plugins []plugin.Plugin) map[plugin.Plugin]struct{} {
+	usedPlugins := make(map[plugin.Plugin]struct{})
+	for _, stmt := range componentStmt.ErrStmt().List {
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok {
+			continue
+		}
+		cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+		if !ok {
+			continue
+		}
+		errVar, ok := cond.X.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		hookStmts := make([]ast.Stmt, 0)
+		for _, p := range plugins {
+			failurePlugin, ok := p.(plugin.FailurePlugin)
+			if !ok {
+				continue
+			}
+			stmts := failurePlugin.OnFailure(ctx, componentStmt.Name(), errVar)
+			if len(stmts) > 0 {
+				usedPlugins[p] = struct{}{}
+			}
+			hookStmts = append(hookStmts, stmts...)
+		}
+		ifStmt.Body.List = append(hookStmts, ifStmt.Body.List...)
+	}
+	return usedPlugins
+}
+
+// applyFlowHooks adds statements of flow plugins to the beginning of a flow function
+// and before every return statement of the flow function, so statements of AfterFlow
+// are called when the flow fails too. Hooks aren't applied if a strategy doesn't know a name of the flow.
+func (f *flowGen) applyFlowHooks(call ComponentCall) {
+	fn, ok := call.Fn().(*ast.FuncLit)
+	if !ok || f.flowName == "" {
+		return
+	}
+
+	ctx := &BlockContext{
+		Input:     fn.Type.Params,
+		Output:    fn.Type.Results,
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
+	}
+	for _, field := range ctx.Input.List {
+		if len(field.Names) > 0 {
+			ctx.Vars[fields.GetTypeKey(ctx.TypesInfo, field.Type)] = field.Names[0]
+		}
+	}
+
+	flowPlugins := make([]plugin.Plugin, 0)
+	for _, p := range f.pluginsFor("", "") {
+		if _, ok := p.(plugin.FlowPlugin); ok {
+			flowPlugins = append(flowPlugins, p)
+		}
+	}
+	if len(flowPlugins) == 0 {
+		return
+	}
+
+	usedPlugins := make(map[plugin.Plugin]struct{})
+	beforeStmts := make([]ast.Stmt, 0)
+	for _, p := range flowPlugins {
+		stmts := p.(plugin.FlowPlugin).BeforeFlow(ctx, f.flowName)
+		if len(stmts) > 0 {
+			usedPlugins[p] = struct{}{}
+		}
+		beforeStmts = append(beforeStmts, stmts...)
+	}
+
+	insertBeforeReturns(fn.Body, func(returnStmt *ast.ReturnStmt) []ast.Stmt {
+		outputFields := flowOutputFields(ctx.Output, returnStmt)
+		afterStmts := make([]ast.Stmt, 0)
+		for _, p := range flowPlugins {
+			stmts := p.(plugin.FlowPlugin).AfterFlow(ctx, f.flowName, outputFields)
+			if len(stmts) > 0 {
+				usedPlugins[p] = struct{}{}
+			}
+			afterStmts = append(afterStmts, stmts...)
+		}
+		return afterStmts
+	})
+	fn.Body.List = append(beforeStmts, fn.Body.List...)
+
+	for _, p := range flowPlugins {
+		if _, ok := usedPlugins[p]; !ok {
+			continue
+		}
+		for _, impr := range p.Imports() {
+			f.AddImport(impr)
+		}
+	}
+}
+
+// flowOutputFields returns result variables of a flow for a return statement.
+// Results which aren't variables, for example nil, don't have names.
+func flowOutputFields(output *ast.FieldList, returnStmt *ast.ReturnStmt) []*ast.Field {
+	if output == nil || len(returnStmt.Results) != len(output.List) {
+		return nil
+	}
+	outputFields := make([]*ast.Field, 0, len(output.List))
+	for index, field := range output.List {
+		outputField := &ast.Field{Type: field.Type}
+		if v, ok := returnStmt.Results[index].(*ast.Ident); ok {
+			outputField.Names = []*ast.Ident{v}
+		}
+		outputFields = append(outputFields, outputField)
+	}
+	return outputFields
+}
+
+// insertBeforeReturns adds statements before every return statement of a function body.
+// Closures aren't changed, because their return statements don't return from the function.
+func insertBeforeReturns(body *ast.BlockStmt, build func(*ast.ReturnStmt) []ast.Stmt) {
+	lists := make([]*[]ast.Stmt, 0)
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			lists = append(lists, &n.List)
+		case *ast.CaseClause:
+			lists = append(lists, &n.Body)
+		case *ast.CommClause:
+			lists = append(lists, &n.Body)
+		}
+		return true
+	})
+
+	for _, list := range lists {
+		stmts := make([]ast.Stmt, 0, len(*list))
+		for _, stmt := range *list {
+			if returnStmt, ok := stmt.(*ast.ReturnStmt); ok {
+				stmts = append(stmts, build(returnStmt)...)
+			}
+			stmts = append(stmts, stmt)
+		}
+		*list = stmts
+	}
+}
+
+// BuildCompensationStmt builds a statement which records a compensation of a succeeded step.
+// Arguments of the compensation are copied, because variables can be changed by next steps.
+// A context of the flow is passed to the compensation instead of a context of the block,
+// because the context of the block can be canceled before compensations are called.
+func (f *flowGen) BuildCompensationStmt(ctx *BlockContext, compensationCall ComponentCall) ast.Stmt {
+	if f.compensations == nil {
+		f.AddImport(effeLibrary)
+		f.compensations = ast.NewIdent("compensations")
+	}
+
+	var args, copiedArgs []ast.Expr
+	ctx.values().bindCall(compensationCall)
+	if compensationCall.Input() != nil {
+		for _, inputField := range compensationCall.Input().List {
+			if fields.GetTypeKey(f.TypesInfo(), inputField.Type) == fields.GetTypeKey(f.TypesInfo(), buildContextType()) {
+				if f.compensationCtx == nil {
+					f.compensationCtx = ast.NewIdent("ctx")
+				}
+				args = append(args, f.compensationCtx)
+				continue
+			}
+			inputVars := ctx.BuildInputVars(&ast.FieldList{List: []*ast.Field{inputField}})
+			args = append(args, inputVars[0].Names[0])
+			copiedArgs = append(copiedArgs, inputVars[0].Names[0])
+		}
+	}
+
+	call := &ast.CallExpr{
+		Fun:  compensationCall.Fn(),
+		Args: args,
+	}
+	var body []ast.Stmt
+	if compensationCall.Output() == nil || len(compensationCall.Output().List) == 0 {
+		body = []ast.Stmt{
+			&ast.ExprStmt{X: call},
+			&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}},
+		}
+	} else {
+		body = []ast.Stmt{
+			&ast.ReturnStmt{Results: []ast.Expr{call}},
+		}
+	}
+
+	addStmt := &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{X: f.compensations, Sel: ast.NewIdent("Add")},
+			Args: []ast.Expr{
+				&ast.FuncLit{
+					Type: &ast.FuncType{
+						Params: &ast.FieldList{},
+						Results: &ast.FieldList{
+							List: []*ast.Field{{Type: ast.NewIdent(errorExpr)}},
+						},
+					},
+					Body: &ast.BlockStmt{List: body},
+				},
+			},
+		},
+	}
+	if len(copiedArgs) == 0 {
+		return addStmt
+	}
+
+	return &ast.BlockStmt{
+		List: []ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: copiedArgs,
+				Tok: token.DEFINE,
+				Rhs: copiedArgs,
+			},
+			addStmt,
+		},
+	}
+}
+
+// buildCompensatedFlowCall wraps a flow for calling compensations of succeeded steps
+// if the flow returns an error.
+func (f *flowGen) buildCompensatedFlowCall(call ComponentCall) (ComponentCall, error) {
+	if call.Output() == nil || fields.FindFieldWithTypeInfo(f.typesInfo, call.Output().List, ast.NewIdent(errorExpr)) == nil {
+		return nil, errors.New("flow with compensations must return an error")
+	}
+
+	ctx := &BlockContext{
+		Input:     new(ast.FieldList),
+		Output:    new(ast.FieldList),
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
+	}
+	ctx.CalculateInput([]ComponentCall{call})
+	ctx.CalculateOutput([]ComponentCall{call})
+	ctx.Output.List = sortComponentOutput(ctx.Output.List)
+	if f.compensationCtx != nil {
+		f.AddImport(contextLibrary)
+		ctxField := ctx.AddInput(buildContextType())
+		f.compensationCtx.Name = ctxField.Names[0].Name
+	}
+	ctx.Input.List = sortComponentInput(ctx.Input.List)
+
+	componentStmt := f.BuildComponentStmt(ctx, call, nil)
+	errVar := ctx.Vars[errorExpr]
+	failureReturnStmt, _ := buildFailureReturnStmt(ctx.Output, ctx.Vars, "", f.typesInfo, ctx.ValueName)
+
+	block := &ast.BlockStmt{
+		List: []ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{f.compensations},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{
+					&ast.UnaryExpr{
+						Op: token.AND,
+						X: &ast.CompositeLit{
+							Type: &ast.SelectorExpr{X: ast.NewIdent("effe"), Sel: ast.NewIdent("Compensations")},
+						},
+					},
+				},
+			},
+			componentStmt.Stmt(),
+			&ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X:  errVar,
+					Op: token.NEQ,
+					Y:  ast.NewIdent("nil"),
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						&ast.AssignStmt{
+							Lhs: []ast.Expr{errVar},
+							Tok: token.ASSIGN,
+							Rhs: []ast.Expr{
+								&ast.CallExpr{
+									Fun:  &ast.SelectorExpr{X: f.compensations, Sel: ast.NewIdent("Run")},
+									Args: []ast.Expr{errVar},
+								},
+							},
+						},
+					},
+				},
+			},
+			failureReturnStmt,
+		},
+	}
+
+	return componentCall{
+		fn: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  ctx.Input,
+				Results: ctx.Output,
+			},
+			Body: block,
+		},
+		name:       call.Name(),
+		input:      ctx.Input,
+		output:     ctx.Output,
+		valueNames: ctx.values(),
+	}, nil
+}
@@ -3,7 +3,9 @@ package strategies
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	goTypes "go/types"
+	"strconv"
 
 	"github.com/GettEngineering/effe/fields"
 	"github.com/GettEngineering/effe/types"
@@ -50,6 +52,31 @@ func GenWrapComponentCall(f FlowGen, wComponent types.Component) (ComponentCall,
 		}
 	}
 
+	if component.Finally != nil {
+		var finallyCall ComponentCall
+		finallyCall, err = GenSimpleComponentCall(f, component.Finally)
+		if err != nil {
+			return nil, err
+		}
+		if finallyCall.Output() != nil && len(finallyCall.Output().List) > 0 {
+			return nil, errors.Errorf("finally function %s in %s must not return values", component.Finally.Name(), component.Name())
+		}
+
+		// The finally function is deferred right after steps which return its arguments.
+		var index int
+		for callIndex, call := range calls {
+			if call.Output() == nil || finallyCall.Input() == nil {
+				continue
+			}
+			for _, input := range finallyCall.Input().List {
+				if fields.FindFieldWithTypeInfo(f.TypesInfo(), call.Output().List, input.Type) != nil {
+					index = callIndex + 1
+				}
+			}
+		}
+		calls = append(calls[:index], append([]ComponentCall{deferredCall{finallyCall}}, calls[index:]...)...)
+	}
+
 	return BuildMultiComponentCall(f, calls, failureCall), nil
 }
 
@@ -58,15 +85,98 @@ func GenSimpleComponentCall(f FlowGen, sComponent types.Component) (ComponentCal
 	if !ok {
 		return nil, errors.Errorf("component %s is not a component with type SimpleComponent", sComponent.Name())
 	}
-	return &componentCall{
+	values := make(valueNames)
+	call := &componentCall{
 		fn: &ast.SelectorExpr{
 			X:   ast.NewIdent(f.ServiceName()),
 			Sel: ast.NewIdent(strcase.ToCamel(component.FuncName.Name)),
 		},
-		input:  component.Input,
-		output: component.Output,
-		name:   component.Name(),
-	}, nil
+		input:      bindNamedInputs(values, component.Input, component.NamedInputs),
+		output:     bindNamedOutputs(values, component.Output, component.As),
+		name:       component.Name(),
+		valueNames: values,
+	}
+
+	if component.Compensate != nil {
+		compensationCall, err := GenSimpleComponentCall(f, component.Compensate)
+		if err != nil {
+			return nil, err
+		}
+		output := compensationCall.Output()
+		if output != nil && (len(output.List) > 1 || len(output.List) == 1 && fields.GetTypeStrName(output.List[0].Type) != errorExpr) {
+			return nil, errors.Errorf("compensation %s of %s must return nothing or only an error", component.Compensate.Name(), component.Name())
+		}
+		call.compensation = compensationCall
+	}
+	return call, nil
+}
+
+// bindNamedInputs binds arguments with names from a list to named values.
+// Arguments declared in one field are split, because each argument takes own value.
+func bindNamedInputs(values valueNames, input *ast.FieldList, names []string) *ast.FieldList {
+	if input == nil {
+		return input
+	}
+
+	boundInput := &ast.FieldList{}
+	for _, inputField := range input.List {
+		if len(inputField.Names) < 2 {
+			boundInput.List = append(boundInput.List, bindNamedInput(values, inputField, names))
+			continue
+		}
+		for _, name := range inputField.Names {
+			boundInput.List = append(boundInput.List, bindNamedInput(values, &ast.Field{
+				Names: []*ast.Ident{name},
+				Type:  inputField.Type,
+			}, names))
+		}
+	}
+	return boundInput
+}
+
+// bindNamedInput returns a copy of an argument which is bound to a named value.
+// Fields of a step are shared by all calls of the step, so names are bound to copies.
+func bindNamedInput(values valueNames, inputField *ast.Field, names []string) *ast.Field {
+	if len(inputField.Names) == 0 {
+		return inputField
+	}
+	for _, name := range names {
+		if inputField.Names[0].Name == name {
+			field := copyField(inputField)
+			values[field] = name
+			return field
+		}
+	}
+	return inputField
+}
+
+// bindNamedOutputs binds values returned by a step to a name. Errors are never named.
+func bindNamedOutputs(values valueNames, output *ast.FieldList, name string) *ast.FieldList {
+	if output == nil || name == "" {
+		return output
+	}
+
+	boundOutput := &ast.FieldList{}
+	for _, outputField := range output.List {
+		if fields.GetTypeStrName(outputField.Type) == errorExpr {
+			boundOutput.List = append(boundOutput.List, outputField)
+			continue
+		}
+		field := copyField(outputField)
+		values[field] = name
+		boundOutput.List = append(boundOutput.List, field)
+	}
+	return boundOutput
+}
+
+func copyField(f *ast.Field) *ast.Field {
+	return &ast.Field{
+		Doc:     f.Doc,
+		Names:   f.Names,
+		Type:    f.Type,
+		Tag:     f.Tag,
+		Comment: f.Comment,
+	}
 }
 
 func GenCaseComponentCall(f FlowGen, cComponent types.Component) (ComponentCall, error) {
@@ -103,8 +213,13 @@ func GenDecisionComponentCall(f FlowGen, dComponent types.Component) (ComponentC
 		return nil, errors.Errorf("component %s is not a component with type DecisionComponent", dComponent.Name())
 	}
 
+	caseComponents := component.Cases
+	if component.Default != nil {
+		caseComponents = append(caseComponents[:len(caseComponents):len(caseComponents)], component.Default)
+	}
+
 	calls := make([]ComponentCall, 0)
-	for _, caseComponent := range component.Cases {
+	for _, caseComponent := range caseComponents {
 		call, err := GenCaseComponentCall(f, caseComponent)
 		if err != nil {
 			return nil, err
@@ -113,10 +228,12 @@ func GenDecisionComponentCall(f FlowGen, dComponent types.Component) (ComponentC
 	}
 
 	ctx := &BlockContext{
-		Input:   &ast.FieldList{},
-		Output:  &ast.FieldList{},
-		Vars:    make(map[string]*ast.Ident),
-		Builder: f.VarBuilder(),
+		Input:     &ast.FieldList{},
+		Output:    &ast.FieldList{},
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
 	}
 
 	ctx.AddInput(component.TagType)
@@ -134,8 +251,9 @@ func GenDecisionComponentCall(f FlowGen, dComponent types.Component) (ComponentC
 		}
 	}
 
-	if !switchReturnErr {
-		ctx.addOutput(ast.NewIdent("error"))
+	// The generated default case returns an error, if the default case is not declared.
+	if !switchReturnErr && component.Default == nil {
+		ctx.addOutput(&ast.Field{Type: ast.NewIdent("error")})
 	}
 
 	ctx.Output.List = sortComponentOutput(ctx.Output.List)
@@ -145,7 +263,7 @@ func GenDecisionComponentCall(f FlowGen, dComponent types.Component) (ComponentC
 		sharedVars[k] = v
 	}
 
-	tagVar, ok := sharedVars[fields.GetTypeStrName(component.TagType)]
+	tagVar, ok := sharedVars[fields.GetTypeKey(f.TypesInfo(), component.TagType)]
 	if !ok {
 		return nil, errors.Errorf("can't find a variable for switch in component %s", component.Name())
 	}
@@ -161,25 +279,132 @@ func GenDecisionComponentCall(f FlowGen, dComponent types.Component) (ComponentC
 		componentStmt := f.BuildComponentStmt(ctx, caseCall, nil)
 		block := f.ApplyPlugins(ctx, componentStmt)
 
-		returnStmt := BuildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo())
+		returnStmt := buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName)
 		block.List = append(block.List, returnStmt)
 
 		switchStmt.Body.List = append(switchStmt.Body.List, &ast.CaseClause{
 			Body: block.List,
-			List: []ast.Expr{component.Cases[index].Tag},
+			List: caseComponents[index].Tags(),
 		})
 	}
-	failMsg := fmt.Sprintf("unsupported logic by %s", fields.GetTypeStrName(component.Tag))
-	returnStmt, fmtUsed := BuildFailureReturnStmt(ctx.Output, nil, failMsg, f.TypesInfo())
-	if fmtUsed {
-		f.AddImport(fmtLibrary)
+
+	if component.Default == nil {
+		failMsg := fmt.Sprintf("unsupported logic by %s", fields.GetTypeStrName(component.Tag))
+		returnStmt, fmtUsed := buildFailureReturnStmt(ctx.Output, nil, failMsg, f.TypesInfo(), ctx.ValueName)
+		if fmtUsed {
+			f.AddImport(fmtLibrary)
+		}
+
+		switchStmt.Body.List = append(switchStmt.Body.List, &ast.CaseClause{
+			Body: []ast.Stmt{
+				returnStmt,
+			},
+		})
 	}
+	ctx.Input.List = sortComponentInput(ctx.Input.List)
 
-	switchStmt.Body.List = append(switchStmt.Body.List, &ast.CaseClause{
-		Body: []ast.Stmt{
-			returnStmt,
+	return componentCall{
+		fn: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  ctx.Input,
+				Results: ctx.Output,
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					switchStmt,
+				},
+			},
 		},
+		name:       dComponent.Name(),
+		input:      ctx.Input,
+		output:     ctx.Output,
+		valueNames: ctx.values(),
+	}, nil
+}
+
+//nolint:funlen
+func GenIfComponentCall(f FlowGen, iComponent types.Component) (ComponentCall, error) {
+	component, ok := iComponent.(*types.IfComponent)
+	if !ok {
+		return nil, errors.Errorf("component %s is not a component with type IfComponent", iComponent.Name())
+	}
+
+	predicateCall, err := GenSimpleComponentCall(f, component.Predicate)
+	if err != nil {
+		return nil, err
+	}
+	predicateOutput := predicateCall.Output()
+	if predicateOutput == nil || len(predicateOutput.List) == 0 || len(predicateOutput.List) > 2 ||
+		fields.GetTypeStrName(predicateOutput.List[0].Type) != "bool" ||
+		len(predicateOutput.List) == 2 && fields.GetTypeStrName(predicateOutput.List[1].Type) != errorExpr {
+		return nil, errors.Errorf("predicate %s in %s must return bool and optionally an error", component.Predicate.Name(), component.Name())
+	}
+
+	thenCalls, err := GenComponentCalls(f, component.Then...)
+	if err != nil {
+		return nil, err
+	}
+	calls := []ComponentCall{BuildMultiComponentCall(f, thenCalls, nil)}
+
+	if len(component.Else) > 0 {
+		elseCalls, err := GenComponentCalls(f, component.Else...)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, BuildMultiComponentCall(f, elseCalls, nil))
+	}
+
+	ctx := &BlockContext{
+		Input:     &ast.FieldList{},
+		Output:    &ast.FieldList{},
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
+	}
+
+	ctx.CalculateInput([]ComponentCall{predicateCall})
+	for _, call := range calls {
+		ctx.CalculateInput([]ComponentCall{call})
+		ctx.CalculateOutput([]ComponentCall{call})
+	}
+	if len(predicateOutput.List) == 2 && fields.FindFieldWithTypeInfo(f.TypesInfo(), ctx.OutputList(), ast.NewIdent(errorExpr)) == nil {
+		ctx.addOutput(&ast.Field{Type: ast.NewIdent(errorExpr)})
+	}
+	ctx.Output.List = sortComponentOutput(ctx.Output.List)
+
+	predicateStmt := f.BuildComponentStmt(ctx, predicateCall, nil)
+	// The first output variable of the predicate is bool, it's checked above.
+	predicateVar := predicateStmt.OutputFields()[0].Names[0]
+	block := f.ApplyPlugins(ctx, predicateStmt)
+
+	sharedVars := make(map[string]*ast.Ident)
+	for k, v := range ctx.Vars {
+		sharedVars[k] = v
+	}
+
+	branches := make([]*ast.BlockStmt, len(calls))
+	for index, call := range calls {
+		ctx.Vars = make(map[string]*ast.Ident)
+		for k, v := range sharedVars {
+			ctx.Vars[k] = v
+		}
+
+		componentStmt := f.BuildComponentStmt(ctx, call, nil)
+		branches[index] = f.ApplyPlugins(ctx, componentStmt)
+		branches[index].List = append(branches[index].List, buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName))
+	}
+
+	// Both branches end with a return statement, so the else branch follows the if statement.
+	block.List = append(block.List, &ast.IfStmt{
+		Cond: predicateVar,
+		Body: branches[0],
 	})
+	if len(branches) > 1 {
+		block.List = append(block.List, branches[1].List...)
+	} else {
+		block.List = append(block.List, buildReturnStmt(ctx.Output, sharedVars, f.TypesInfo(), ctx.ValueName))
+	}
 	ctx.Input.List = sortComponentInput(ctx.Input.List)
 
 	return componentCall{
@@ -188,15 +413,1083 @@ func GenDecisionComponentCall(f FlowGen, dComponent types.Component) (ComponentC
 				Params:  ctx.Input,
 				Results: ctx.Output,
 			},
+			Body: block,
+		},
+		name:       iComponent.Name(),
+		input:      ctx.Input,
+		output:     ctx.Output,
+		valueNames: ctx.values(),
+	}, nil
+}
+
+func checkParallelCalls(info *goTypes.Info, name *ast.Ident, calls []ComponentCall) error {
+	values := make(valueNames)
+	for _, call := range calls {
+		values.bindCall(call)
+	}
+	for index, call := range calls {
+		if call.Output() == nil {
+			continue
+		}
+		for _, output := range call.Output().List {
+			if fields.GetTypeStrName(output.Type) == errorExpr {
+				continue
+			}
+			for otherIndex, other := range calls {
+				if otherIndex == index {
+					continue
+				}
+				if other.Input() != nil && values.usesOutput(info, other.Input().List, output) {
+					return errors.Errorf("step %s in %s uses an output of step %s", other.Name(), name, call.Name())
+				}
+				if otherIndex > index && other.Output() != nil && values.findField(info, other.Output().List, output) != nil {
+					return errors.Errorf("steps %s and %s in %s return the same value %s", call.Name(), other.Name(), name, values.key(info, output))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func buildParallelGoStmt(f FlowGen, ctx *BlockContext, call ComponentCall, wg, errOnce, errVar, cancel *ast.Ident) *ast.GoStmt {
+	body := []ast.Stmt{
+		&ast.DeferStmt{
+			Call: &ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: wg, Sel: ast.NewIdent("Done")},
+			},
+		},
+	}
+
+	callExpr := &ast.CallExpr{
+		Fun:  call.Fn(),
+		Args: getNamesFromFieldList(ctx.BuildInputVars(call.Input())),
+	}
+
+	if call.Output() == nil || len(call.Output().List) == 0 {
+		body = append(body, &ast.ExprStmt{X: callExpr})
+	} else {
+		var localErrVar *ast.Ident
+		lhs := make([]ast.Expr, 0, len(call.Output().List))
+		for _, output := range call.Output().List {
+			if fields.GetTypeStrName(output.Type) == errorExpr {
+				localErrVar = f.VarBuilder()(output.Type)
+				lhs = append(lhs, localErrVar)
+				continue
+			}
+			v, _ := ctx.genFieldVariable(output)
+			lhs = append(lhs, v)
+		}
+
+		assignStmt := &ast.AssignStmt{
+			Lhs: lhs,
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{callExpr},
+		}
+		if localErrVar != nil && len(lhs) == 1 {
+			assignStmt.Tok = token.DEFINE
+		} else if localErrVar != nil {
+			body = append(body, &ast.DeclStmt{
+				Decl: &ast.GenDecl{
+					Tok: token.VAR,
+					Specs: []ast.Spec{
+						&ast.ValueSpec{
+							Names: []*ast.Ident{localErrVar},
+							Type:  ast.NewIdent(errorExpr),
+						},
+					},
+				},
+			})
+		}
+		body = append(body, assignStmt)
+
+		if localErrVar != nil {
+			onceBody := []ast.Stmt{
+				&ast.AssignStmt{
+					Lhs: []ast.Expr{errVar},
+					Tok: token.ASSIGN,
+					Rhs: []ast.Expr{localErrVar},
+				},
+			}
+			if cancel != nil {
+				onceBody = append(onceBody, &ast.ExprStmt{X: &ast.CallExpr{Fun: cancel}})
+			}
+			body = append(body, &ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X:  localErrVar,
+					Op: token.NEQ,
+					Y:  ast.NewIdent("nil"),
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						&ast.ExprStmt{
+							X: &ast.CallExpr{
+								Fun: &ast.SelectorExpr{X: errOnce, Sel: ast.NewIdent("Do")},
+								Args: []ast.Expr{
+									&ast.FuncLit{
+										Type: &ast.FuncType{Params: &ast.FieldList{}},
+										Body: &ast.BlockStmt{List: onceBody},
+									},
+								},
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	return &ast.GoStmt{
+		Call: &ast.CallExpr{
+			Fun: &ast.FuncLit{
+				Type: &ast.FuncType{Params: &ast.FieldList{}},
+				Body: &ast.BlockStmt{List: body},
+			},
+		},
+	}
+}
+
+//nolint:funlen
+func GenParallelComponentCall(f FlowGen, pComponent types.Component) (ComponentCall, error) {
+	component, ok := pComponent.(*types.ParallelComponent)
+	if !ok {
+		return nil, errors.Errorf("component %s is not a component with type ParallelComponent", pComponent.Name())
+	}
+
+	childCalls, err := GenComponentCalls(f, component.Children...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every child is wrapped to a block for applying plugins and handling errors inside a goroutine.
+	calls := make([]ComponentCall, len(childCalls))
+	for index, childCall := range childCalls {
+		calls[index] = BuildMultiComponentCall(f, []ComponentCall{childCall}, nil)
+	}
+
+	if err = checkParallelCalls(f.TypesInfo(), component.Name(), calls); err != nil {
+		return nil, err
+	}
+
+	ctx := &BlockContext{
+		Input:     new(ast.FieldList),
+		Output:    new(ast.FieldList),
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
+	}
+	ctx.CalculateInput(calls)
+	ctx.CalculateOutput(calls)
+	ctx.Output.List = sortComponentOutput(ctx.Output.List)
+	ctx.Input.List = sortComponentInput(ctx.Input.List)
+
+	f.AddImport(syncLibrary)
+	wg := ast.NewIdent("wg")
+	varSpecs := []ast.Spec{
+		&ast.ValueSpec{
+			Names: []*ast.Ident{wg},
+			Type:  &ast.SelectorExpr{X: ast.NewIdent(syncLibrary), Sel: ast.NewIdent("WaitGroup")},
+		},
+	}
+
+	var (
+		errVar, errOnce, cancel *ast.Ident
+		returnErr               bool
+	)
+	for _, output := range ctx.OutputList() {
+		if fields.GetTypeStrName(output.Type) == errorExpr {
+			returnErr = true
+			continue
+		}
+		v, _ := ctx.genFieldVariable(output)
+		varSpecs = append(varSpecs, &ast.ValueSpec{
+			Names: []*ast.Ident{v},
+			Type:  output.Type,
+		})
+	}
+
+	block := &ast.BlockStmt{}
+	if returnErr {
+		errOnce = ast.NewIdent("errOnce")
+		errVar, _ = ctx.genVariable(ast.NewIdent(errorExpr))
+		varSpecs = append(varSpecs,
+			&ast.ValueSpec{
+				Names: []*ast.Ident{errOnce},
+				Type:  &ast.SelectorExpr{X: ast.NewIdent(syncLibrary), Sel: ast.NewIdent("Once")},
+			},
+			&ast.ValueSpec{
+				Names: []*ast.Ident{errVar},
+				Type:  ast.NewIdent(errorExpr),
+			},
+		)
+
+		ctxField := ctx.FindInputByType(buildContextType())
+		if ctxField != nil {
+			f.AddImport(contextLibrary)
+			cancel = ast.NewIdent("cancel")
+			block.List = append(block.List,
+				&ast.AssignStmt{
+					Lhs: []ast.Expr{ctxField.Names[0], cancel},
+					Tok: token.DEFINE,
+					Rhs: []ast.Expr{
+						&ast.CallExpr{
+							Fun:  &ast.SelectorExpr{X: ast.NewIdent(contextLibrary), Sel: ast.NewIdent("WithCancel")},
+							Args: []ast.Expr{ctxField.Names[0]},
+						},
+					},
+				},
+				&ast.DeferStmt{Call: &ast.CallExpr{Fun: cancel}},
+			)
+		}
+	}
+
+	block.List = append(block.List,
+		buildVarDeclStmt(varSpecs),
+		&ast.ExprStmt{
+			X: &ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: wg, Sel: ast.NewIdent("Add")},
+				Args: []ast.Expr{
+					&ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(len(calls))},
+				},
+			},
+		},
+	)
+
+	for _, call := range calls {
+		block.List = append(block.List, buildParallelGoStmt(f, ctx, call, wg, errOnce, errVar, cancel))
+	}
+
+	block.List = append(block.List, &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{X: wg, Sel: ast.NewIdent("Wait")},
+		},
+	})
+
+	if returnErr {
+		failureReturnStmt, _ := buildFailureReturnStmt(ctx.Output, ctx.Vars, "", f.TypesInfo(), ctx.ValueName)
+		block.List = append(block.List, &ast.IfStmt{
+			Cond: &ast.BinaryExpr{
+				X:  errVar,
+				Op: token.NEQ,
+				Y:  ast.NewIdent("nil"),
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{failureReturnStmt},
+			},
+		})
+	}
+	block.List = append(block.List, buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName))
+
+	return componentCall{
+		fn: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  ctx.Input,
+				Results: ctx.Output,
+			},
+			Body: block,
+		},
+		name:       pComponent.Name(),
+		input:      ctx.Input,
+		output:     ctx.Output,
+		valueNames: ctx.values(),
+	}, nil
+}
+
+func buildRetryWaitStmts(ctx *BlockContext, ctxVar, backoff *ast.Ident, typesInfo *goTypes.Info) []ast.Stmt {
+	var ctxErrReturnStmt *ast.ReturnStmt
+	if ctxVar != nil {
+		ctxErrReturnStmt = buildReturnStmt(ctx.Output, ctx.Vars, typesInfo, ctx.ValueName)
+		for index, output := range ctx.OutputList() {
+			if fields.GetTypeStrName(output.Type) == errorExpr {
+				ctxErrReturnStmt.Results[index] = &ast.CallExpr{
+					Fun: &ast.SelectorExpr{X: ctxVar, Sel: ast.NewIdent("Err")},
+				}
+			}
+		}
+	}
+
+	switch {
+	case ctxVar != nil && backoff != nil:
+		return []ast.Stmt{
+			&ast.SelectStmt{
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{
+						&ast.CommClause{
+							Comm: &ast.ExprStmt{
+								X: &ast.UnaryExpr{
+									Op: token.ARROW,
+									X: &ast.CallExpr{
+										Fun: &ast.SelectorExpr{X: ctxVar, Sel: ast.NewIdent("Done")},
+									},
+								},
+							},
+							Body: []ast.Stmt{ctxErrReturnStmt},
+						},
+						&ast.CommClause{
+							Comm: &ast.ExprStmt{
+								X: &ast.UnaryExpr{
+									Op: token.ARROW,
+									X: &ast.CallExpr{
+										Fun:  &ast.SelectorExpr{X: ast.NewIdent(timeLibrary), Sel: ast.NewIdent("After")},
+										Args: []ast.Expr{backoff},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{backoff},
+				Tok: token.MUL_ASSIGN,
+				Rhs: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "2"}},
+			},
+		}
+	case ctxVar != nil:
+		return []ast.Stmt{
+			&ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X: &ast.CallExpr{
+						Fun: &ast.SelectorExpr{X: ctxVar, Sel: ast.NewIdent("Err")},
+					},
+					Op: token.NEQ,
+					Y:  ast.NewIdent("nil"),
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{ctxErrReturnStmt},
+				},
+			},
+		}
+	case backoff != nil:
+		return []ast.Stmt{
+			&ast.ExprStmt{
+				X: &ast.CallExpr{
+					Fun:  &ast.SelectorExpr{X: ast.NewIdent(timeLibrary), Sel: ast.NewIdent("Sleep")},
+					Args: []ast.Expr{backoff},
+				},
+			},
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{backoff},
+				Tok: token.MUL_ASSIGN,
+				Rhs: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "2"}},
+			},
+		}
+	}
+	return []ast.Stmt{}
+}
+
+//nolint:funlen
+func GenRetryComponentCall(f FlowGen, rComponent types.Component) (ComponentCall, error) {
+	component, ok := rComponent.(*types.RetryComponent)
+	if !ok {
+		return nil, errors.Errorf("component %s is not a component with type RetryComponent", rComponent.Name())
+	}
+
+	childCall, err := f.GenComponentCall(component.Child)
+	if err != nil {
+		return nil, err
+	}
+	// The child is wrapped to a block for applying plugins on every attempt.
+	call := BuildMultiComponentCall(f, []ComponentCall{childCall}, nil)
+	if call.Output() == nil || fields.FindFieldWithTypeInfo(f.TypesInfo(), call.Output().List, ast.NewIdent(errorExpr)) == nil {
+		return nil, errors.Errorf("component %s in %s must return an error", component.Child.Name(), component.Name())
+	}
+
+	ctx := &BlockContext{
+		Input:     new(ast.FieldList),
+		Output:    new(ast.FieldList),
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
+	}
+	ctx.CalculateInput([]ComponentCall{call})
+	ctx.CalculateOutput([]ComponentCall{call})
+	ctx.Output.List = sortComponentOutput(ctx.Output.List)
+
+	varSpecs := make([]ast.Spec, 0, len(ctx.OutputList()))
+	for _, output := range ctx.OutputList() {
+		v, _ := ctx.genFieldVariable(output)
+		varSpecs = append(varSpecs, &ast.ValueSpec{
+			Names: []*ast.Ident{v},
+			Type:  output.Type,
+		})
+	}
+	errVar := ctx.Vars[errorExpr]
+
+	block := &ast.BlockStmt{
+		List: []ast.Stmt{
+			buildVarDeclStmt(varSpecs),
+		},
+	}
+
+	addExprImports(f, component.Attempts)
+	attempts := ast.NewIdent("attempts")
+	block.List = append(block.List, &ast.AssignStmt{
+		Lhs: []ast.Expr{attempts},
+		Tok: token.DEFINE,
+		Rhs: []ast.Expr{component.Attempts},
+	})
+
+	var backoff *ast.Ident
+	if component.Backoff != nil {
+		f.AddImport(timeLibrary)
+		addExprImports(f, component.Backoff)
+		backoff = ast.NewIdent("backoff")
+		block.List = append(block.List, &ast.AssignStmt{
+			Lhs: []ast.Expr{backoff},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{component.Backoff},
+		})
+	}
+
+	attempt := ast.NewIdent("attempt")
+	var breakCond ast.Expr = &ast.BinaryExpr{
+		X: &ast.BinaryExpr{
+			X:  errVar,
+			Op: token.EQL,
+			Y:  ast.NewIdent("nil"),
+		},
+		Op: token.LOR,
+		Y: &ast.BinaryExpr{
+			X:  attempt,
+			Op: token.GEQ,
+			Y:  attempts,
+		},
+	}
+
+	if component.RetryIf != nil {
+		retryIfCall, err := GenSimpleComponentCall(f, component.RetryIf)
+		if err != nil {
+			return nil, err
+		}
+		if retryIfCall.Output() == nil || len(retryIfCall.Output().List) != 1 || fields.GetTypeStrName(retryIfCall.Output().List[0].Type) != "bool" {
+			return nil, errors.Errorf("component %s in %s must return only bool", component.RetryIf.Name(), component.Name())
+		}
+		ctx.values().bindCall(retryIfCall)
+		breakCond = &ast.BinaryExpr{
+			X:  breakCond,
+			Op: token.LOR,
+			Y: &ast.UnaryExpr{
+				Op: token.NOT,
+				X: &ast.CallExpr{
+					Fun:  retryIfCall.Fn(),
+					Args: getNamesFromFieldList(ctx.BuildInputVars(retryIfCall.Input())),
+				},
+			},
+		}
+	}
+
+	ctx.Input.List = sortComponentInput(ctx.Input.List)
+	var ctxVar *ast.Ident
+	ctxField := ctx.FindInputByType(buildContextType())
+	if ctxField != nil {
+		ctxVar = ctxField.Names[0]
+	}
+
+	outputFields, _ := ctx.BuildOutputVars(call.Output())
+	loopBody := []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: getNamesFromFieldList(outputFields),
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{
+				&ast.CallExpr{
+					Fun:  call.Fn(),
+					Args: getNamesFromFieldList(ctx.BuildInputVars(call.Input())),
+				},
+			},
+		},
+		&ast.IfStmt{
+			Cond: breakCond,
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{&ast.BranchStmt{Tok: token.BREAK}},
+			},
+		},
+	}
+	loopBody = append(loopBody, buildRetryWaitStmts(ctx, ctxVar, backoff, f.TypesInfo())...)
+
+	failureReturnStmt, _ := buildFailureReturnStmt(ctx.Output, ctx.Vars, "", f.TypesInfo(), ctx.ValueName)
+	block.List = append(block.List,
+		&ast.ForStmt{
+			Init: &ast.AssignStmt{
+				Lhs: []ast.Expr{attempt},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "1"}},
+			},
+			Post: &ast.IncDecStmt{X: attempt, Tok: token.INC},
+			Body: &ast.BlockStmt{List: loopBody},
+		},
+		&ast.IfStmt{
+			Cond: &ast.BinaryExpr{
+				X:  errVar,
+				Op: token.NEQ,
+				Y:  ast.NewIdent("nil"),
+			},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{failureReturnStmt},
+			},
+		},
+		buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName),
+	)
+
+	return componentCall{
+		fn: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  ctx.Input,
+				Results: ctx.Output,
+			},
+			Body: block,
+		},
+		name:       rComponent.Name(),
+		input:      ctx.Input,
+		output:     ctx.Output,
+		valueNames: ctx.values(),
+	}, nil
+}
+
+func GenTimeoutComponentCall(f FlowGen, tComponent types.Component) (ComponentCall, error) {
+	component, ok := tComponent.(*types.TimeoutComponent)
+	if !ok {
+		return nil, errors.Errorf("component %s is not a component with type TimeoutComponent", tComponent.Name())
+	}
+
+	childCalls, err := GenComponentCalls(f, component.Children...)
+	if err != nil {
+		return nil, err
+	}
+	call := BuildMultiComponentCall(f, childCalls, nil)
+
+	ctx := &BlockContext{
+		Input:     new(ast.FieldList),
+		Output:    new(ast.FieldList),
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
+	}
+	ctx.CalculateInput([]ComponentCall{call})
+	ctx.CalculateOutput([]ComponentCall{call})
+	ctx.Output.List = sortComponentOutput(ctx.Output.List)
+
+	ctxVar := ctx.AddInput(buildContextType()).Names[0]
+	ctx.Input.List = sortComponentInput(ctx.Input.List)
+
+	f.AddImport(contextLibrary)
+	addExprImports(f, component.Duration)
+	durationType := &ast.SelectorExpr{X: ast.NewIdent(timeLibrary), Sel: ast.NewIdent("Duration")}
+	durationVar := f.VarBuilder()(durationType)
+	cancel := ast.NewIdent("cancel")
+	block := &ast.BlockStmt{
+		List: []ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{durationVar},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{buildDurationExpr(f, component.Duration)},
+			},
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{ctxVar, cancel},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{
+					&ast.CallExpr{
+						Fun:  &ast.SelectorExpr{X: ast.NewIdent(contextLibrary), Sel: ast.NewIdent("WithTimeout")},
+						Args: []ast.Expr{ctxVar, durationVar},
+					},
+				},
+			},
+			&ast.DeferStmt{Call: &ast.CallExpr{Fun: cancel}},
+		},
+	}
+
+	componentStmt := f.BuildComponentStmt(ctx, call, nil)
+	block.List = append(block.List, componentStmt.Stmt())
+
+	errVar, ok := ctx.Vars[errorExpr]
+	if ok {
+		f.AddImport(effeLibrary)
+		block.List = append(block.List, &ast.IfStmt{
+			Cond: &ast.BinaryExpr{
+				X: &ast.BinaryExpr{
+					X:  errVar,
+					Op: token.NEQ,
+					Y:  ast.NewIdent("nil"),
+				},
+				Op: token.LAND,
+				Y: &ast.BinaryExpr{
+					X: &ast.CallExpr{
+						Fun: &ast.SelectorExpr{X: ctxVar, Sel: ast.NewIdent("Err")},
+					},
+					Op: token.EQL,
+					Y:  &ast.SelectorExpr{X: ast.NewIdent(contextLibrary), Sel: ast.NewIdent("DeadlineExceeded")},
+				},
+			},
 			Body: &ast.BlockStmt{
 				List: []ast.Stmt{
-					switchStmt,
+					&ast.AssignStmt{
+						Lhs: []ast.Expr{errVar},
+						Tok: token.ASSIGN,
+						Rhs: []ast.Expr{
+							&ast.UnaryExpr{
+								Op: token.AND,
+								X: &ast.CompositeLit{
+									Type: &ast.SelectorExpr{X: ast.NewIdent("effe"), Sel: ast.NewIdent("TimeoutError")},
+									Elts: []ast.Expr{
+										&ast.KeyValueExpr{
+											Key:   ast.NewIdent("Name"),
+											Value: buildTimeoutErrorName(f, call, durationVar),
+										},
+										&ast.KeyValueExpr{
+											Key:   ast.NewIdent("Err"),
+											Value: errVar,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	if componentStmt.ErrStmt() != nil {
+		block.List = append(block.List, componentStmt.ErrStmt().List...)
+	}
+	block.List = append(block.List, buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName))
+
+	return componentCall{
+		fn: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  ctx.Input,
+				Results: ctx.Output,
+			},
+			Body: block,
+		},
+		name:       tComponent.Name(),
+		input:      ctx.Input,
+		output:     ctx.Output,
+		valueNames: ctx.values(),
+	}, nil
+}
+
+// buildDurationExpr returns a duration of a timeout. Untyped constants are converted to time.Duration.
+func buildDurationExpr(f FlowGen, duration ast.Expr) ast.Expr {
+	if named, ok := f.TypesInfo().TypeOf(duration).(*goTypes.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == timeLibrary && obj.Name() == "Duration" {
+			return duration
+		}
+	}
+	f.AddImport(timeLibrary)
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent(timeLibrary), Sel: ast.NewIdent("Duration")},
+		Args: []ast.Expr{duration},
+	}
+}
+
+// buildTimeoutErrorName returns a name of a block with a timeout for TimeoutError.
+// The name contains a name of the flow, names of steps in the block and the duration of the timeout.
+func buildTimeoutErrorName(f FlowGen, call ComponentCall, durationVar *ast.Ident) ast.Expr {
+	name := f.FlowName()
+	if call.Name() != nil {
+		name += "." + call.Name().Name
+	}
+	return &ast.BinaryExpr{
+		X: &ast.BinaryExpr{
+			X:  &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(name + " (")},
+			Op: token.ADD,
+			Y: &ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: durationVar, Sel: ast.NewIdent("String")},
+			},
+		},
+		Op: token.ADD,
+		Y:  &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(")")},
+	}
+}
+
+// buildForEachCallStmt builds a call of the block for a single element.
+// Only the collected value and the error are kept, other outputs are dropped.
+func buildForEachCallStmt(f FlowGen, ctx *BlockContext, call ComponentCall, collect ast.Expr) (ast.Stmt, *ast.Ident, *ast.Ident) {
+	ctx.values().bindCall(call)
+	callExpr := &ast.CallExpr{
+		Fun:  call.Fn(),
+		Args: getNamesFromFieldList(ctx.BuildInputVars(call.Input())),
+	}
+
+	var resultVar, errVar *ast.Ident
+	if call.Output() == nil {
+		return &ast.ExprStmt{X: callExpr}, nil, nil
+	}
+	lhs := make([]ast.Expr, 0, len(call.Output().List))
+	for _, output := range call.Output().List {
+		switch {
+		case fields.GetTypeStrName(output.Type) == errorExpr:
+			errVar = f.VarBuilder()(output.Type)
+			lhs = append(lhs, errVar)
+		case collect != nil && fields.GetTypeKey(f.TypesInfo(), output.Type) == fields.GetTypeKey(f.TypesInfo(), collect):
+			resultVar = f.VarBuilder()(output.Type)
+			lhs = append(lhs, resultVar)
+		default:
+			lhs = append(lhs, ast.NewIdent("_"))
+		}
+	}
+	if resultVar == nil && errVar == nil {
+		return &ast.ExprStmt{X: callExpr}, nil, nil
+	}
+
+	return &ast.AssignStmt{
+		Lhs: lhs,
+		Tok: token.DEFINE,
+		Rhs: []ast.Expr{callExpr},
+	}, resultVar, errVar
+}
+
+func buildForEachGoStmt(callStmt ast.Stmt, resultsVar, resultVar, localErrVar, errVar, errOnce, failed, cancel, sem, wg, index, itemVar *ast.Ident, itemType ast.Expr) *ast.GoStmt {
+	body := []ast.Stmt{
+		&ast.DeferStmt{
+			Call: &ast.CallExpr{
+				Fun: &ast.FuncLit{
+					Type: &ast.FuncType{Params: &ast.FieldList{}},
+					Body: &ast.BlockStmt{
+						List: []ast.Stmt{
+							&ast.ExprStmt{X: &ast.UnaryExpr{Op: token.ARROW, X: sem}},
+							&ast.ExprStmt{
+								X: &ast.CallExpr{
+									Fun: &ast.SelectorExpr{X: wg, Sel: ast.NewIdent("Done")},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		callStmt,
+	}
+
+	if localErrVar != nil {
+		onceBody := []ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{errVar},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{localErrVar},
+			},
+			&ast.ExprStmt{
+				X: &ast.CallExpr{Fun: ast.NewIdent("close"), Args: []ast.Expr{failed}},
+			},
+		}
+		if cancel != nil {
+			onceBody = append(onceBody, &ast.ExprStmt{X: &ast.CallExpr{Fun: cancel}})
+		}
+		ifBody := []ast.Stmt{
+			&ast.ExprStmt{
+				X: &ast.CallExpr{
+					Fun: &ast.SelectorExpr{X: errOnce, Sel: ast.NewIdent("Do")},
+					Args: []ast.Expr{
+						&ast.FuncLit{
+							Type: &ast.FuncType{Params: &ast.FieldList{}},
+							Body: &ast.BlockStmt{List: onceBody},
+						},
+					},
+				},
+			},
+		}
+		if resultVar != nil {
+			ifBody = append(ifBody, &ast.ReturnStmt{})
+		}
+		body = append(body, &ast.IfStmt{
+			Cond: &ast.BinaryExpr{
+				X:  localErrVar,
+				Op: token.NEQ,
+				Y:  ast.NewIdent("nil"),
+			},
+			Body: &ast.BlockStmt{List: ifBody},
+		})
+	}
+
+	params := &ast.FieldList{}
+	args := []ast.Expr{}
+	if resultVar != nil {
+		body = append(body, &ast.AssignStmt{
+			Lhs: []ast.Expr{&ast.IndexExpr{X: resultsVar, Index: index}},
+			Tok: token.ASSIGN,
+			Rhs: []ast.Expr{resultVar},
+		})
+		params.List = append(params.List, &ast.Field{Names: []*ast.Ident{index}, Type: ast.NewIdent("int")})
+		args = append(args, index)
+	}
+	params.List = append(params.List, &ast.Field{Names: []*ast.Ident{itemVar}, Type: itemType})
+	args = append(args, itemVar)
+
+	return &ast.GoStmt{
+		Call: &ast.CallExpr{
+			Fun: &ast.FuncLit{
+				Type: &ast.FuncType{Params: params},
+				Body: &ast.BlockStmt{List: body},
+			},
+			Args: args,
+		},
+	}
+}
+
+// buildForEachLoopStmt builds a loop which acquires the semaphore for every item.
+// If failed isn't nil, the loop stops after the first error.
+func buildForEachLoopStmt(sliceVar, index, itemVar, sem, failed *ast.Ident, emptyStruct ast.Expr, startStmts []ast.Stmt) ast.Stmt {
+	loopBody := []ast.Stmt{&ast.SendStmt{Chan: sem, Value: emptyStruct}}
+	var label *ast.Ident
+	if failed != nil {
+		label = ast.NewIdent("loop")
+		loopBody = append(loopBody, &ast.SelectStmt{
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					&ast.CommClause{
+						Comm: &ast.ExprStmt{X: &ast.UnaryExpr{Op: token.ARROW, X: failed}},
+						Body: []ast.Stmt{&ast.BranchStmt{Tok: token.BREAK, Label: label}},
+					},
+					&ast.CommClause{},
+				},
+			},
+		})
+	}
+	loopBody = append(loopBody, startStmts...)
+
+	loop := &ast.RangeStmt{
+		Key:   index,
+		Value: itemVar,
+		Tok:   token.DEFINE,
+		X:     sliceVar,
+		Body:  &ast.BlockStmt{List: loopBody},
+	}
+	if label == nil {
+		return loop
+	}
+	return &ast.LabeledStmt{Label: label, Stmt: loop}
+}
+
+//nolint:funlen,gocognit
+func GenForEachComponentCall(f FlowGen, feComponent types.Component) (ComponentCall, error) {
+	component, ok := feComponent.(*types.ForEachComponent)
+	if !ok {
+		return nil, errors.Errorf("component %s is not a component with type ForEachComponent", feComponent.Name())
+	}
+
+	childCalls, err := GenComponentCalls(f, component.Children...)
+	if err != nil {
+		return nil, err
+	}
+	call := BuildMultiComponentCall(f, childCalls, nil)
+	if lit, ok := call.Fn().(*ast.FuncLit); ok {
+		lit.Type = &ast.FuncType{
+			Params:  copyFieldListWithoutPos(lit.Type.Params),
+			Results: copyFieldListWithoutPos(lit.Type.Results),
+		}
+	}
+
+	var returnErr bool
+	if call.Output() != nil {
+		returnErr = fields.FindFieldWithTypeInfo(f.TypesInfo(), call.Output().List, ast.NewIdent(errorExpr)) != nil
+	}
+	if component.Collect != nil && (call.Output() == nil || fields.FindFieldWithTypeInfo(f.TypesInfo(), call.Output().List, component.Collect) == nil) {
+		return nil, errors.Errorf("steps in %s don't return %s", component.Name(), fields.GetTypeStrName(component.Collect))
+	}
+
+	ctx := &BlockContext{
+		Input:     new(ast.FieldList),
+		Output:    new(ast.FieldList),
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
+	}
+	sliceVar := ctx.AddInput(component.SliceType).Names[0]
+	itemVar, _ := ctx.genVariable(component.ItemType)
+
+	block := &ast.BlockStmt{}
+
+	var resultsVar *ast.Ident
+	if component.Collect != nil {
+		resultsType := &ast.ArrayType{Elt: component.Collect}
+		ctx.addOutput(&ast.Field{Type: resultsType})
+		resultsVar, _ = ctx.genVariable(resultsType)
+
+		makeArgs := []ast.Expr{
+			resultsType,
+			&ast.BasicLit{Kind: token.INT, Value: "0"},
+			&ast.CallExpr{Fun: ast.NewIdent("len"), Args: []ast.Expr{sliceVar}},
+		}
+		if component.Concurrency != nil {
+			makeArgs = []ast.Expr{resultsType, makeArgs[2]}
+		}
+		block.List = append(block.List, &ast.AssignStmt{
+			Lhs: []ast.Expr{resultsVar},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.CallExpr{Fun: ast.NewIdent("make"), Args: makeArgs}},
+		})
+	}
+	if returnErr {
+		ctx.addOutput(&ast.Field{Type: ast.NewIdent(errorExpr)})
+	}
+
+	callStmt, resultVar, localErrVar := buildForEachCallStmt(f, ctx, call, component.Collect)
+	ctx.Input.List = sortComponentInput(ctx.Input.List)
+
+	if component.Concurrency == nil {
+		loopBody := []ast.Stmt{callStmt}
+		if localErrVar != nil {
+			ctx.Vars[errorExpr] = localErrVar
+			failureReturnStmt, _ := buildFailureReturnStmt(ctx.Output, ctx.Vars, "", f.TypesInfo(), ctx.ValueName)
+			delete(ctx.Vars, errorExpr)
+			loopBody = append(loopBody, &ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X:  localErrVar,
+					Op: token.NEQ,
+					Y:  ast.NewIdent("nil"),
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{failureReturnStmt},
+				},
+			})
+		}
+		if resultVar != nil {
+			loopBody = append(loopBody, &ast.AssignStmt{
+				Lhs: []ast.Expr{resultsVar},
+				Tok: token.ASSIGN,
+				Rhs: []ast.Expr{
+					&ast.CallExpr{Fun: ast.NewIdent("append"), Args: []ast.Expr{resultsVar, resultVar}},
+				},
+			})
+		}
+		block.List = append(block.List,
+			&ast.RangeStmt{
+				Key:   ast.NewIdent("_"),
+				Value: itemVar,
+				Tok:   token.DEFINE,
+				X:     sliceVar,
+				Body:  &ast.BlockStmt{List: loopBody},
+			},
+			buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName),
+		)
+	} else {
+		f.AddImport(syncLibrary)
+		addExprImports(f, component.Concurrency)
+
+		wg := ast.NewIdent("wg")
+		varSpecs := []ast.Spec{
+			&ast.ValueSpec{
+				Names: []*ast.Ident{wg},
+				Type:  &ast.SelectorExpr{X: ast.NewIdent(syncLibrary), Sel: ast.NewIdent("WaitGroup")},
+			},
+		}
+
+		emptyStruct := &ast.CompositeLit{Type: ast.NewIdent("struct{}")}
+
+		// failed is closed after the first error, so new items aren't started.
+		var errVar, errOnce, failed, cancel *ast.Ident
+		if localErrVar != nil {
+			errOnce = ast.NewIdent("errOnce")
+			failed = ast.NewIdent("failed")
+			errVar, _ = ctx.genVariable(ast.NewIdent(errorExpr))
+			varSpecs = append(varSpecs,
+				&ast.ValueSpec{
+					Names: []*ast.Ident{errOnce},
+					Type:  &ast.SelectorExpr{X: ast.NewIdent(syncLibrary), Sel: ast.NewIdent("Once")},
+				},
+				&ast.ValueSpec{
+					Names: []*ast.Ident{errVar},
+					Type:  ast.NewIdent(errorExpr),
+				},
+				&ast.ValueSpec{
+					Names: []*ast.Ident{failed},
+					Values: []ast.Expr{
+						&ast.CallExpr{
+							Fun:  ast.NewIdent("make"),
+							Args: []ast.Expr{&ast.ChanType{Dir: ast.SEND | ast.RECV, Value: emptyStruct.Type}},
+						},
+					},
+				},
+			)
+
+			ctxField := ctx.FindInputByType(buildContextType())
+			if ctxField != nil {
+				f.AddImport(contextLibrary)
+				cancel = ast.NewIdent("cancel")
+				block.List = append(block.List,
+					&ast.AssignStmt{
+						Lhs: []ast.Expr{ctxField.Names[0], cancel},
+						Tok: token.DEFINE,
+						Rhs: []ast.Expr{
+							&ast.CallExpr{
+								Fun:  &ast.SelectorExpr{X: ast.NewIdent(contextLibrary), Sel: ast.NewIdent("WithCancel")},
+								Args: []ast.Expr{ctxField.Names[0]},
+							},
+						},
+					},
+					&ast.DeferStmt{Call: &ast.CallExpr{Fun: cancel}},
+				)
+			}
+		}
+
+		sem := ast.NewIdent("sem")
+		index := ast.NewIdent("_")
+		if resultVar != nil {
+			index = ast.NewIdent("index")
+		}
+		block.List = append(block.List,
+			buildVarDeclStmt(varSpecs),
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{sem},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{
+					&ast.CallExpr{
+						Fun: ast.NewIdent("make"),
+						Args: []ast.Expr{
+							&ast.ChanType{Dir: ast.SEND | ast.RECV, Value: emptyStruct.Type},
+							component.Concurrency,
+						},
+					},
+				},
+			},
+			buildForEachLoopStmt(sliceVar, index, itemVar, sem, failed, emptyStruct, []ast.Stmt{
+				&ast.ExprStmt{
+					X: &ast.CallExpr{
+						Fun:  &ast.SelectorExpr{X: wg, Sel: ast.NewIdent("Add")},
+						Args: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "1"}},
+					},
 				},
+				buildForEachGoStmt(callStmt, resultsVar, resultVar, localErrVar, errVar, errOnce, failed, cancel, sem, wg, index, itemVar, component.ItemType),
+			}),
+			&ast.ExprStmt{
+				X: &ast.CallExpr{
+					Fun: &ast.SelectorExpr{X: wg, Sel: ast.NewIdent("Wait")},
+				},
+			},
+		)
+
+		if errVar != nil {
+			failureReturnStmt, _ := buildFailureReturnStmt(ctx.Output, ctx.Vars, "", f.TypesInfo(), ctx.ValueName)
+			block.List = append(block.List, &ast.IfStmt{
+				Cond: &ast.BinaryExpr{
+					X:  errVar,
+					Op: token.NEQ,
+					Y:  ast.NewIdent("nil"),
+				},
+				Body: &ast.BlockStmt{
+					List: []ast.Stmt{failureReturnStmt},
+				},
+			})
+		}
+		block.List = append(block.List, buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName))
+	}
+
+	return componentCall{
+		fn: &ast.FuncLit{
+			Type: &ast.FuncType{
+				Params:  ctx.Input,
+				Results: ctx.Output,
 			},
+			Body: block,
 		},
-		name:   dComponent.Name(),
-		input:  ctx.Input,
-		output: ctx.Output,
+		name:       feComponent.Name(),
+		input:      ctx.Input,
+		output:     ctx.Output,
+		valueNames: ctx.values(),
 	}, nil
 }
 
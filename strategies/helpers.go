@@ -3,6 +3,7 @@ package strategies
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 	"sort"
 	"strings"
@@ -21,7 +22,101 @@ func getNamesFromFieldList(list []*ast.Field) []ast.Expr {
 	return vars
 }
 
+// addExprImports adds imports of packages which are used in an expression
+// copied from a flow declaration.
+func addExprImports(f FlowGen, expr ast.Expr) {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkgIdent, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		pkgName, ok := f.TypesInfo().ObjectOf(pkgIdent).(*types.PkgName)
+		if ok {
+			f.AddImport(pkgName.Imported().Path())
+		}
+		return true
+	})
+}
+
+// copyFieldListWithoutPos returns a copy of a list of fields with types without positions,
+// names of fields are variables of a block, so they aren't copied. Types of fields are copied from different declarations, so the printer breaks lines
+// between fields if positions of the types are kept.
+func copyFieldListWithoutPos(list *ast.FieldList) *ast.FieldList {
+	if list == nil {
+		return nil
+	}
+	copied := &ast.FieldList{List: make([]*ast.Field, len(list.List))}
+	for index, field := range list.List {
+		copied.List[index] = &ast.Field{
+			Names: field.Names,
+			Type:  copyExprWithoutPos(field.Type),
+		}
+	}
+	return copied
+}
+
+// copyExprWithoutPos returns a copy of a type expression without positions.
+// Unknown expressions aren't copied.
+func copyExprWithoutPos(expr ast.Expr) ast.Expr {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return ast.NewIdent(expr.Name)
+	case *ast.SelectorExpr:
+		return &ast.SelectorExpr{X: copyExprWithoutPos(expr.X), Sel: ast.NewIdent(expr.Sel.Name)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: copyExprWithoutPos(expr.X)}
+	case *ast.ParenExpr:
+		return &ast.ParenExpr{X: copyExprWithoutPos(expr.X)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: copyExprWithoutPos(expr.Elt)}
+	case *ast.ArrayType:
+		var length ast.Expr
+		if expr.Len != nil {
+			length = copyExprWithoutPos(expr.Len)
+		}
+		return &ast.ArrayType{Len: length, Elt: copyExprWithoutPos(expr.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: copyExprWithoutPos(expr.Key), Value: copyExprWithoutPos(expr.Value)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: expr.Dir, Value: copyExprWithoutPos(expr.Value)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: copyFieldListWithoutPos(expr.Params), Results: copyFieldListWithoutPos(expr.Results)}
+	case *ast.BasicLit:
+		return &ast.BasicLit{Kind: expr.Kind, Value: expr.Value}
+	default:
+		return expr
+	}
+}
+
+func buildContextType() ast.Expr {
+	return &ast.SelectorExpr{
+		X:   ast.NewIdent(contextLibrary),
+		Sel: ast.NewIdent("Context"),
+	}
+}
+
+func buildVarDeclStmt(specs []ast.Spec) *ast.DeclStmt {
+	decl := &ast.GenDecl{
+		Tok:   token.VAR,
+		Specs: specs,
+	}
+	if len(specs) > 1 {
+		decl.Lparen = 1
+	}
+	return &ast.DeclStmt{Decl: decl}
+}
+
 func BuildReturnStmt(output *ast.FieldList, vars map[string]*ast.Ident, typesInfo *types.Info) *ast.ReturnStmt {
+	return buildReturnStmt(output, vars, typesInfo, nil)
+}
+
+// buildReturnStmt works as BuildReturnStmt, but variables of outputs bound to named values
+// are found by names, which are returned by valueName.
+func buildReturnStmt(output *ast.FieldList, vars map[string]*ast.Ident, typesInfo *types.Info, valueName func(*ast.Field) string) *ast.ReturnStmt {
 	returnStmt := &ast.ReturnStmt{}
 	for _, output := range output.List {
 		typeStrName := fields.GetTypeStrName(output.Type)
@@ -30,7 +125,11 @@ func BuildReturnStmt(output *ast.FieldList, vars map[string]*ast.Ident, typesInf
 			continue
 		}
 		if vars != nil {
-			v, ok := vars[typeStrName]
+			var name string
+			if valueName != nil {
+				name = valueName(output)
+			}
+			v, ok := vars[fields.GetValueKey(typesInfo, name, output.Type)]
 			if ok {
 				returnStmt.Results = append(returnStmt.Results, &ast.Ident{
 					Name: v.Name,
@@ -84,8 +183,13 @@ func buildNilVarByType(t ast.Expr, typesInfo *types.Info) ast.Expr {
 }
 
 func BuildFailureReturnStmt(output *ast.FieldList, vars map[string]*ast.Ident, defaultErrMsg string, typesInfo *types.Info) (*ast.ReturnStmt, bool) {
+	return buildFailureReturnStmt(output, vars, defaultErrMsg, typesInfo, nil)
+}
+
+// buildFailureReturnStmt works as BuildFailureReturnStmt, but variables are found as in buildReturnStmt.
+func buildFailureReturnStmt(output *ast.FieldList, vars map[string]*ast.Ident, defaultErrMsg string, typesInfo *types.Info, valueName func(*ast.Field) string) (*ast.ReturnStmt, bool) {
 	fmtUsed := false
-	returnStmt := BuildReturnStmt(output, vars, typesInfo)
+	returnStmt := buildReturnStmt(output, vars, typesInfo, valueName)
 	for index, output := range output.List {
 		typeStrName := fields.GetTypeStrName(output.Type)
 		if typeStrName != "error" {
@@ -159,10 +263,12 @@ func BuildMultiComponentName(calls []ComponentCall) *ast.Ident {
 
 func BuildMultiComponentCall(f FlowGen, calls []ComponentCall, failureCall ComponentCall) ComponentCall {
 	ctx := &BlockContext{
-		Input:   new(ast.FieldList),
-		Output:  new(ast.FieldList),
-		Vars:    make(map[string]*ast.Ident),
-		Builder: f.VarBuilder(),
+		Input:     new(ast.FieldList),
+		Output:    new(ast.FieldList),
+		Vars:      make(map[string]*ast.Ident),
+		Builder:   f.VarBuilder(),
+		TypesInfo: f.TypesInfo(),
+		Flow:      f.FlowName(),
 	}
 
 	ctx.CalculateInput(calls)
@@ -172,9 +278,23 @@ func BuildMultiComponentCall(f FlowGen, calls []ComponentCall, failureCall Compo
 	block := &ast.BlockStmt{}
 
 	for _, call := range calls {
+		if _, ok := call.(deferredCall); ok {
+			block.List = append(block.List, &ast.DeferStmt{
+				Call: &ast.CallExpr{
+					Fun:  call.Fn(),
+					Args: getNamesFromFieldList(ctx.BuildInputVars(call.Input())),
+				},
+			})
+			continue
+		}
+
 		componentStmt := f.BuildComponentStmt(ctx, call, failureCall)
 		componentBlock := f.ApplyPlugins(ctx, componentStmt)
 		block.List = append(block.List, componentBlock.List...)
+
+		if compensatedCall, ok := call.(CompensatedCall); ok && compensatedCall.Compensation() != nil {
+			block.List = append(block.List, f.BuildCompensationStmt(ctx, compensatedCall.Compensation()))
+		}
 	}
 
 	fnType := &ast.FuncType{
@@ -183,7 +303,7 @@ func BuildMultiComponentCall(f FlowGen, calls []ComponentCall, failureCall Compo
 	}
 	ctx.Input.List = sortComponentInput(ctx.Input.List)
 	name := BuildMultiComponentName(calls)
-	returnStmt := BuildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo())
+	returnStmt := buildReturnStmt(ctx.Output, ctx.Vars, f.TypesInfo(), ctx.ValueName)
 	block.List = append(block.List, returnStmt)
 
 	return &componentCall{
@@ -193,6 +313,7 @@ func BuildMultiComponentCall(f FlowGen, calls []ComponentCall, failureCall Compo
 			Type: fnType,
 			Body: block,
 		},
-		name: name,
+		name:       name,
+		valueNames: ctx.values(),
 	}
 }
@@ -0,0 +1,89 @@
+package strategies
+
+import "github.com/GettEngineering/effe/plugin"
+
+// Scope describes a component which plugins are applied to.
+// Flow hooks of plugins are applied with a scope without a step and a kind,
+// so they aren't selected by ForSteps and ForKinds.
+type Scope struct {
+	// Flow is a name of a function which declares a flow
+	Flow string
+	// Directives are comments //effe:<directive> of a function which declares a flow
+	Directives []string
+	// Step is a name of a component as it's passed to plugins, for example Step1
+	Step string
+	// Kind is a type of a component, for example SimpleComponent or DecisionComponent
+	Kind string
+}
+
+// Selector decides whether a plugin is applied to a component
+type Selector func(Scope) bool
+
+// ForFlows selects components of flows with names
+func ForFlows(names ...string) Selector {
+	return func(s Scope) bool {
+		return contains(names, s.Flow)
+	}
+}
+
+// ForSteps selects components with names
+func ForSteps(names ...string) Selector {
+	return func(s Scope) bool {
+		return contains(names, s.Step)
+	}
+}
+
+// ForKinds selects components with types
+func ForKinds(kinds ...string) Selector {
+	return func(s Scope) bool {
+		return contains(kinds, s.Kind)
+	}
+}
+
+// ForDirective selects components of flows which are marked by a comment //effe:<directive>
+//
+// Example:
+//
+//	//effe:payments
+//	func Charge() error {
+//	    effe.BuildFlow(
+//	        effe.Step(step1),
+//	    )
+//	    return nil
+//	}
+func ForDirective(directive string) Selector {
+	return func(s Scope) bool {
+		return contains(s.Directives, directive)
+	}
+}
+
+// Not selects components which aren't selected by a selector
+func Not(selector Selector) Selector {
+	return func(s Scope) bool {
+		return !selector(s)
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// scopedPlugin is a plugin which is applied to components selected by all selectors
+type scopedPlugin struct {
+	plugin    plugin.Plugin
+	selectors []Selector
+}
+
+func (p scopedPlugin) match(s Scope) bool {
+	for _, selector := range p.selectors {
+		if !selector(s) {
+			return false
+		}
+	}
+	return true
+}
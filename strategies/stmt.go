@@ -4,10 +4,15 @@ import "go/ast"
 
 type componentStmt struct {
 	componentName string
+	kind          string
 	inputFields   []*ast.Field
 	outputFields  []*ast.Field
 	stmt          ast.Stmt
 	failureBlock  *ast.BlockStmt
+
+	// declaredInput and declaredOutput are parameters and results of a component as they are declared
+	declaredInput  []*ast.Field
+	declaredOutput []*ast.Field
 }
 
 func (c componentStmt) Stmt() ast.Stmt {
@@ -26,10 +31,34 @@ func (c componentStmt) OutputFields() []*ast.Field {
 	return c.outputFields
 }
 
+// DeclaredInputFields returns parameters of a component, they are in the same order as InputFields
+func (c componentStmt) DeclaredInputFields() []*ast.Field {
+	return c.declaredInput
+}
+
+// DeclaredOutputFields returns results of a component, they are in the same order as OutputFields
+func (c componentStmt) DeclaredOutputFields() []*ast.Field {
+	return c.declaredOutput
+}
+
 func (c componentStmt) Name() string {
 	return c.componentName
 }
 
+// Kind returns a type of a component, for example SimpleComponent
+func (c componentStmt) Kind() string {
+	return c.kind
+}
+
+// componentKind returns a type of a component of a statement. Statements,
+// which aren't built by BuildComponentStmt, don't have a type.
+func componentKind(c ComponentStmt) string {
+	if k, ok := c.(interface{ Kind() string }); ok {
+		return k.Kind()
+	}
+	return ""
+}
+
 type ComponentStmt interface {
 	Stmt() ast.Stmt
 	InputFields() []*ast.Field
@@ -0,0 +1,19 @@
+// +build effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(loadBooking),
+		effe.Parallel(
+			effe.Step(reserveHotel, effe.Compensate(cancelHotel)),
+			effe.Step(reserveFlight, effe.Compensate(cancelFlight)),
+		),
+		effe.Step(chargeCard),
+	)
+	return nil
+}
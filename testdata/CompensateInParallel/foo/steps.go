@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+)
+
+type Booking struct {
+	ID string
+}
+
+type Reservation struct {
+	ID string
+}
+
+type Ticket struct {
+	ID string
+}
+
+func loadBooking() func(id string) (*Booking, error) {
+	return func(id string) (*Booking, error) {
+		return &Booking{ID: id}, nil
+	}
+}
+
+func reserveHotel() func(ctx context.Context, booking *Booking) (*Reservation, error) {
+	return func(ctx context.Context, booking *Booking) (*Reservation, error) {
+		return &Reservation{}, nil
+	}
+}
+
+func cancelHotel() func(ctx context.Context, reservation *Reservation) error {
+	return func(ctx context.Context, reservation *Reservation) error {
+		return nil
+	}
+}
+
+func reserveFlight() func(ctx context.Context, booking *Booking) (*Ticket, error) {
+	return func(ctx context.Context, booking *Booking) (*Ticket, error) {
+		return &Ticket{}, nil
+	}
+}
+
+func cancelFlight() func(ticket *Ticket) {
+	return func(ticket *Ticket) {}
+}
+
+func chargeCard() func(booking *Booking, reservation *Reservation, ticket *Ticket) error {
+	return func(booking *Booking, reservation *Reservation, ticket *Ticket) error {
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"github.com/GettEngineering/effe"
+	"sync"
+)
+
+func A(service AService) AFunc {
+	return func(ctx5 context.Context, stringVal2 string) error {
+		compensations := &effe.Compensations{}
+		err7 := func(ctx4 context.Context, stringVal string) error {
+			BookingPtrVal4, err6 := service.LoadBooking(stringVal)
+			if err6 != nil {
+				return err6
+			}
+			TicketPtrVal3, ReservationPtrVal3, err6 := func(ctx3 context.Context, BookingPtrVal3 *Booking) (*Ticket, *Reservation, error) {
+				ctx3, cancel := context.WithCancel(ctx3)
+				defer cancel()
+				var (
+					wg                 sync.WaitGroup
+					TicketPtrVal2      *Ticket
+					ReservationPtrVal2 *Reservation
+					errOnce            sync.Once
+					err3               error
+				)
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					var err4 error
+					ReservationPtrVal2, err4 = func(ctx context.Context, BookingPtrVal *Booking) (*Reservation, error) {
+						ReservationPtrVal, err := service.ReserveHotel(ctx, BookingPtrVal)
+						if err != nil {
+							return ReservationPtrVal, err
+						}
+						{
+							ReservationPtrVal := ReservationPtrVal
+							compensations.Add(func() error {
+								return service.CancelHotel(ctx5, ReservationPtrVal)
+							})
+						}
+						return ReservationPtrVal, nil
+					}(ctx3, BookingPtrVal3)
+					if err4 != nil {
+						errOnce.Do(func() {
+							err3 = err4
+							cancel()
+						})
+					}
+				}()
+				go func() {
+					defer wg.Done()
+					var err5 error
+					TicketPtrVal2, err5 = func(ctx2 context.Context, BookingPtrVal2 *Booking) (*Ticket, error) {
+						TicketPtrVal, err2 := service.ReserveFlight(ctx2, BookingPtrVal2)
+						if err2 != nil {
+							return TicketPtrVal, err2
+						}
+						{
+							TicketPtrVal := TicketPtrVal
+							compensations.Add(func() error {
+								service.CancelFlight(TicketPtrVal)
+								return nil
+							})
+						}
+						return TicketPtrVal, nil
+					}(ctx3, BookingPtrVal3)
+					if err5 != nil {
+						errOnce.Do(func() {
+							err3 = err5
+							cancel()
+						})
+					}
+				}()
+				wg.Wait()
+				if err3 != nil {
+					return TicketPtrVal2, ReservationPtrVal2, err3
+				}
+				return TicketPtrVal2, ReservationPtrVal2, nil
+			}(ctx4, BookingPtrVal4)
+			if err6 != nil {
+				return err6
+			}
+			err6 = service.ChargeCard(BookingPtrVal4, ReservationPtrVal3, TicketPtrVal3)
+			if err6 != nil {
+				return err6
+			}
+			return nil
+		}(ctx5, stringVal2)
+		if err7 != nil {
+			err7 = compensations.Run(err7)
+		}
+		return err7
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{cancelFlightFieldFunc: cancelFlight(), cancelHotelFieldFunc: cancelHotel(), chargeCardFieldFunc: chargeCard(), loadBookingFieldFunc: loadBooking(), reserveFlightFieldFunc: reserveFlight(), reserveHotelFieldFunc: reserveHotel()}
+}
+
+type AService interface {
+	CancelFlight(ticket *Ticket)
+	CancelHotel(ctx context.Context, reservation *Reservation) error
+	ChargeCard(booking *Booking, reservation *Reservation, ticket *Ticket) error
+	LoadBooking(id string) (*Booking, error)
+	ReserveFlight(ctx context.Context, booking *Booking) (*Ticket, error)
+	ReserveHotel(ctx context.Context, booking *Booking) (*Reservation, error)
+}
+type AImpl struct {
+	cancelFlightFieldFunc  func(ticket *Ticket)
+	cancelHotelFieldFunc   func(ctx context.Context, reservation *Reservation) error
+	chargeCardFieldFunc    func(booking *Booking, reservation *Reservation, ticket *Ticket) error
+	loadBookingFieldFunc   func(id string) (*Booking, error)
+	reserveFlightFieldFunc func(ctx context.Context, booking *Booking) (*Ticket, error)
+	reserveHotelFieldFunc  func(ctx context.Context, booking *Booking) (*Reservation, error)
+}
+type AFunc func(ctx5 context.Context, stringVal2 string) error
+
+func (a *AImpl) CancelFlight(ticket *Ticket) { a.cancelFlightFieldFunc(ticket) }
+func (a *AImpl) CancelHotel(ctx context.Context, reservation *Reservation) error {
+	return a.cancelHotelFieldFunc(ctx, reservation)
+}
+func (a *AImpl) ChargeCard(booking *Booking, reservation *Reservation, ticket *Ticket) error {
+	return a.chargeCardFieldFunc(booking, reservation, ticket)
+}
+func (a *AImpl) LoadBooking(id string) (*Booking, error) { return a.loadBookingFieldFunc(id) }
+func (a *AImpl) ReserveFlight(ctx context.Context, booking *Booking) (*Ticket, error) {
+	return a.reserveFlightFieldFunc(ctx, booking)
+}
+func (a *AImpl) ReserveHotel(ctx context.Context, booking *Booking) (*Reservation, error) {
+	return a.reserveHotelFieldFunc(ctx, booking)
+}
@@ -0,0 +1,17 @@
+package main
+
+type Charge struct {
+	ID string
+}
+
+func chargeCard() func() (*Charge, error) {
+	return func() (*Charge, error) {
+		return &Charge{}, nil
+	}
+}
+
+func refundCard() func(charge *Charge) error {
+	return func(charge *Charge) error {
+		return nil
+	}
+}
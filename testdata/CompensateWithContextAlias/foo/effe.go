@@ -0,0 +1,15 @@
+// +build effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(reserveHotel, effe.Compensate(cancelHotel)),
+		effe.Step(sendConfirmation),
+	)
+	return nil
+}
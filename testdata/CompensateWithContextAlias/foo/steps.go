@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+)
+
+type Ctx = context.Context
+
+type Booking struct {
+	ID string
+}
+
+type Reservation struct {
+	ID string
+}
+
+func reserveHotel() func(ctx context.Context, booking *Booking) (*Reservation, error) {
+	return func(ctx context.Context, booking *Booking) (*Reservation, error) {
+		return &Reservation{}, nil
+	}
+}
+
+func cancelHotel() func(ctx Ctx, reservation *Reservation) error {
+	return func(ctx Ctx, reservation *Reservation) error {
+		return nil
+	}
+}
+
+func sendConfirmation() func(ctx context.Context, reservation *Reservation) error {
+	return func(ctx context.Context, reservation *Reservation) error {
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"example.com/foo"
+	"github.com/GettEngineering/effe"
+)
+
+func A(service AService) AFunc {
+	return func(ctx2 context.Context, BookingPtrVal2 *Booking) error {
+		compensations := &effe.Compensations{}
+		err2 := func(ctx context.Context, BookingPtrVal *Booking) error {
+			ReservationPtrVal, err := service.ReserveHotel(ctx, BookingPtrVal)
+			if err != nil {
+				return err
+			}
+			{
+				ReservationPtrVal := ReservationPtrVal
+				compensations.Add(func() error {
+					return service.CancelHotel(ctx2, ReservationPtrVal)
+				})
+			}
+			err = service.SendConfirmation(ctx, ReservationPtrVal)
+			if err != nil {
+				return err
+			}
+			return nil
+		}(ctx2, BookingPtrVal2)
+		if err2 != nil {
+			err2 = compensations.Run(err2)
+		}
+		return err2
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{cancelHotelFieldFunc: cancelHotel(), reserveHotelFieldFunc: reserveHotel(), sendConfirmationFieldFunc: sendConfirmation()}
+}
+
+type AService interface {
+	CancelHotel(ctx Ctx, reservation *Reservation) error
+	ReserveHotel(ctx context.Context, booking *Booking) (*Reservation, error)
+	SendConfirmation(ctx context.Context, reservation *Reservation) error
+}
+type AImpl struct {
+	cancelHotelFieldFunc      func(ctx Ctx, reservation *Reservation) error
+	reserveHotelFieldFunc     func(ctx context.Context, booking *Booking) (*Reservation, error)
+	sendConfirmationFieldFunc func(ctx context.Context, reservation *Reservation) error
+}
+type AFunc func(ctx2 context.Context, BookingPtrVal2 *Booking) error
+
+func (a *AImpl) CancelHotel(ctx Ctx, reservation *Reservation) error {
+	return a.cancelHotelFieldFunc(ctx, reservation)
+}
+func (a *AImpl) ReserveHotel(ctx context.Context, booking *Booking) (*Reservation, error) {
+	return a.reserveHotelFieldFunc(ctx, booking)
+}
+func (a *AImpl) SendConfirmation(ctx context.Context, reservation *Reservation) error {
+	return a.sendConfirmationFieldFunc(ctx, reservation)
+}
@@ -0,0 +1,15 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(getStatus),
+		effe.Decision(new(Status),
+			effe.Case(effe.Step(notify)),
+		),
+	)
+	return nil
+}
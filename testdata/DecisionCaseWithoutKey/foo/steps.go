@@ -0,0 +1,13 @@
+package main
+
+type Status string
+
+func getStatus() func() Status {
+	return func() Status {
+		return "new"
+	}
+}
+
+func notify() func() {
+	return func() {}
+}
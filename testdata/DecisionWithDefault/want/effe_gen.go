@@ -0,0 +1,60 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"example.com/foo"
+)
+
+func A(service AService) AFunc {
+	return func(stringVal4 string) {
+		StatusVal3 := service.GetStatus(stringVal4)
+		func(StatusVal2 Status, stringVal3 string) {
+			switch StatusVal2 {
+			case StatusNew, StatusPending:
+				func(stringVal string) {
+					service.Notify(stringVal)
+					return
+				}(stringVal3)
+				return
+			case StatusDone:
+				func(stringVal2 string) {
+					service.Archive(stringVal2)
+					return
+				}(stringVal3)
+				return
+			default:
+				func(StatusVal Status) {
+					service.LogUnknownStatus(StatusVal)
+					return
+				}(StatusVal2)
+				return
+			}
+		}(StatusVal3, stringVal4)
+		return
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{archiveFieldFunc: archive(), getStatusFieldFunc: getStatus(), logUnknownStatusFieldFunc: logUnknownStatus(), notifyFieldFunc: notify()}
+}
+
+type AService interface {
+	Archive(id string)
+	GetStatus(id string) Status
+	LogUnknownStatus(status Status)
+	Notify(id string)
+}
+type AImpl struct {
+	archiveFieldFunc          func(id string)
+	getStatusFieldFunc        func(id string) Status
+	logUnknownStatusFieldFunc func(status Status)
+	notifyFieldFunc           func(id string)
+}
+type AFunc func(stringVal4 string)
+
+func (a *AImpl) Archive(id string)              { a.archiveFieldFunc(id) }
+func (a *AImpl) GetStatus(id string) Status     { return a.getStatusFieldFunc(id) }
+func (a *AImpl) LogUnknownStatus(status Status) { a.logUnknownStatusFieldFunc(status) }
+func (a *AImpl) Notify(id string)               { a.notifyFieldFunc(id) }
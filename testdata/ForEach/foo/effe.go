@@ -0,0 +1,20 @@
+// +build effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(loadOrders),
+		effe.ForEach(new([]Order),
+			effe.Step(notifyUser),
+			effe.Step(chargeOrder),
+			effe.Collect(new(*Receipt)),
+		),
+		effe.Step(saveReceipts),
+	)
+	return nil
+}
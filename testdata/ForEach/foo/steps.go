@@ -0,0 +1,37 @@
+package main
+
+type User struct {
+	ID string
+}
+
+type Order struct {
+	ID string
+}
+
+type Receipt struct {
+	OrderID string
+}
+
+func loadOrders() func(user *User) ([]Order, error) {
+	return func(user *User) ([]Order, error) {
+		return nil, nil
+	}
+}
+
+func chargeOrder() func(user *User, order Order) (*Receipt, error) {
+	return func(user *User, order Order) (*Receipt, error) {
+		return &Receipt{OrderID: order.ID}, nil
+	}
+}
+
+func notifyUser() func(user *User, order Order) error {
+	return func(user *User, order Order) error {
+		return nil
+	}
+}
+
+func saveReceipts() func(receipts []*Receipt) error {
+	return func(receipts []*Receipt) error {
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"example.com/foo"
+)
+
+func A(service AService) AFunc {
+	return func(UserPtrVal3 *User) error {
+		OrderValAr2, err3 := service.LoadOrders(UserPtrVal3)
+		if err3 != nil {
+			return err3
+		}
+		ReceiptPtrValAr2, err3 := func(OrderValAr []Order, UserPtrVal2 *User) ([]*Receipt, error) {
+			ReceiptPtrValAr := make([]*Receipt, 0, len(OrderValAr))
+			for _, OrderVal2 := range OrderValAr {
+				ReceiptPtrVal2, err2 := func(UserPtrVal *User, OrderVal Order) (*Receipt, error) {
+					err := service.NotifyUser(UserPtrVal, OrderVal)
+					if err != nil {
+						return nil, err
+					}
+					ReceiptPtrVal, err := service.ChargeOrder(UserPtrVal, OrderVal)
+					if err != nil {
+						return ReceiptPtrVal, err
+					}
+					return ReceiptPtrVal, nil
+				}(UserPtrVal2, OrderVal2)
+				if err2 != nil {
+					return ReceiptPtrValAr, err2
+				}
+				ReceiptPtrValAr = append(ReceiptPtrValAr, ReceiptPtrVal2)
+			}
+			return ReceiptPtrValAr, nil
+		}(OrderValAr2, UserPtrVal3)
+		if err3 != nil {
+			return err3
+		}
+		err3 = service.SaveReceipts(ReceiptPtrValAr2)
+		if err3 != nil {
+			return err3
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{chargeOrderFieldFunc: chargeOrder(), loadOrdersFieldFunc: loadOrders(), notifyUserFieldFunc: notifyUser(), saveReceiptsFieldFunc: saveReceipts()}
+}
+
+type AService interface {
+	ChargeOrder(user *User, order Order) (*Receipt, error)
+	LoadOrders(user *User) ([]Order, error)
+	NotifyUser(user *User, order Order) error
+	SaveReceipts(receipts []*Receipt) error
+}
+type AImpl struct {
+	chargeOrderFieldFunc  func(user *User, order Order) (*Receipt, error)
+	loadOrdersFieldFunc   func(user *User) ([]Order, error)
+	notifyUserFieldFunc   func(user *User, order Order) error
+	saveReceiptsFieldFunc func(receipts []*Receipt) error
+}
+type AFunc func(UserPtrVal3 *User) error
+
+func (a *AImpl) ChargeOrder(user *User, order Order) (*Receipt, error) {
+	return a.chargeOrderFieldFunc(user, order)
+}
+func (a *AImpl) LoadOrders(user *User) ([]Order, error)   { return a.loadOrdersFieldFunc(user) }
+func (a *AImpl) NotifyUser(user *User, order Order) error { return a.notifyUserFieldFunc(user, order) }
+func (a *AImpl) SaveReceipts(receipts []*Receipt) error   { return a.saveReceiptsFieldFunc(receipts) }
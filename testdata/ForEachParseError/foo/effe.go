@@ -0,0 +1,16 @@
+// +build effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.ForEach(new(Order),
+			effe.Step(chargeOrder),
+		),
+	)
+	return nil
+}
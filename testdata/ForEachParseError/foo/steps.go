@@ -0,0 +1,11 @@
+package main
+
+type Order struct {
+	ID string
+}
+
+func chargeOrder() func(order Order) error {
+	return func(order Order) error {
+		return nil
+	}
+}
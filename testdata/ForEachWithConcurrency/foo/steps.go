@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+)
+
+type Order struct {
+	ID string
+}
+
+type Receipt struct {
+	OrderID string
+}
+
+func loadOrders() func(ctx context.Context) ([]Order, error) {
+	return func(ctx context.Context) ([]Order, error) {
+		return nil, nil
+	}
+}
+
+func chargeOrder() func(ctx context.Context, order Order) (*Receipt, error) {
+	return func(ctx context.Context, order Order) (*Receipt, error) {
+		return &Receipt{OrderID: order.ID}, nil
+	}
+}
+
+func saveReceipts() func(receipts []*Receipt) error {
+	return func(receipts []*Receipt) error {
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"example.com/foo"
+	"sync"
+)
+
+func A(service AService) AFunc {
+	return func(ctx3 context.Context) error {
+		OrderValAr2, err4 := service.LoadOrders(ctx3)
+		if err4 != nil {
+			return err4
+		}
+		ReceiptPtrValAr2, err4 := func(ctx2 context.Context, OrderValAr []Order) ([]*Receipt, error) {
+			ReceiptPtrValAr := make([]*Receipt, len(OrderValAr))
+			ctx2, cancel := context.WithCancel(ctx2)
+			defer cancel()
+			var (
+				wg      sync.WaitGroup
+				errOnce sync.Once
+				err3    error
+				failed  = make(chan struct{})
+			)
+			sem := make(chan struct{}, 4)
+		loop:
+			for index, OrderVal2 := range OrderValAr {
+				sem <- struct{}{}
+				select {
+				case <-failed:
+					break loop
+				default:
+				}
+				wg.Add(1)
+				go func(index int, OrderVal2 Order) {
+					defer func() {
+						<-sem
+						wg.Done()
+					}()
+					ReceiptPtrVal2, err2 := func(ctx context.Context, OrderVal Order) (*Receipt, error) {
+						ReceiptPtrVal, err := service.ChargeOrder(ctx, OrderVal)
+						if err != nil {
+							return ReceiptPtrVal, err
+						}
+						return ReceiptPtrVal, nil
+					}(ctx2, OrderVal2)
+					if err2 != nil {
+						errOnce.Do(func() {
+							err3 = err2
+							close(failed)
+							cancel()
+						})
+						return
+					}
+					ReceiptPtrValAr[index] = ReceiptPtrVal2
+				}(index, OrderVal2)
+			}
+			wg.Wait()
+			if err3 != nil {
+				return ReceiptPtrValAr, err3
+			}
+			return ReceiptPtrValAr, nil
+		}(ctx3, OrderValAr2)
+		if err4 != nil {
+			return err4
+		}
+		err4 = service.SaveReceipts(ReceiptPtrValAr2)
+		if err4 != nil {
+			return err4
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{chargeOrderFieldFunc: chargeOrder(), loadOrdersFieldFunc: loadOrders(), saveReceiptsFieldFunc: saveReceipts()}
+}
+
+type AService interface {
+	ChargeOrder(ctx context.Context, order Order) (*Receipt, error)
+	LoadOrders(ctx context.Context) ([]Order, error)
+	SaveReceipts(receipts []*Receipt) error
+}
+type AImpl struct {
+	chargeOrderFieldFunc  func(ctx context.Context, order Order) (*Receipt, error)
+	loadOrdersFieldFunc   func(ctx context.Context) ([]Order, error)
+	saveReceiptsFieldFunc func(receipts []*Receipt) error
+}
+type AFunc func(ctx3 context.Context) error
+
+func (a *AImpl) ChargeOrder(ctx context.Context, order Order) (*Receipt, error) {
+	return a.chargeOrderFieldFunc(ctx, order)
+}
+func (a *AImpl) LoadOrders(ctx context.Context) ([]Order, error) { return a.loadOrdersFieldFunc(ctx) }
+func (a *AImpl) SaveReceipts(receipts []*Receipt) error          { return a.saveReceiptsFieldFunc(receipts) }
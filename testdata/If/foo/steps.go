@@ -0,0 +1,39 @@
+package main
+
+type User struct {
+	ID string
+}
+
+type Manager struct {
+	ID string
+}
+
+func step1() func(id string) (*User, error) {
+	return func(id string) (*User, error) {
+		return &User{ID: id}, nil
+	}
+}
+
+func isVip() func(user *User) (bool, error) {
+	return func(user *User) (bool, error) {
+		return true, nil
+	}
+}
+
+func assignPersonalManager() func(user *User) (*Manager, error) {
+	return func(user *User) (*Manager, error) {
+		return &Manager{}, nil
+	}
+}
+
+func assignQueue() func(user *User) error {
+	return func(user *User) error {
+		return nil
+	}
+}
+
+func notify() func(user *User, manager *Manager) error {
+	return func(user *User, manager *Manager) error {
+		return nil
+	}
+}
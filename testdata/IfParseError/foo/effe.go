@@ -0,0 +1,14 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.If(isVip,
+			effe.Step(addBonus),
+		),
+	)
+	return nil
+}
@@ -0,0 +1,13 @@
+package main
+
+func isVip() func() bool {
+	return func() bool {
+		return true
+	}
+}
+
+func addBonus() func() error {
+	return func() error {
+		return nil
+	}
+}
@@ -0,0 +1,19 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.If(effe.Step(isVip, effe.As("vip")),
+			effe.Step(assignPersonalManager),
+			effe.Else(
+				effe.Step(assignQueue),
+			),
+		),
+		effe.Step(notify),
+	)
+	return nil
+}
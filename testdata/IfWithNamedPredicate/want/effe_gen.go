@@ -0,0 +1,79 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+func A(service AService) AFunc {
+	return func(stringVal string) error {
+		UserPtrVal4, err4 := service.Step1(stringVal)
+		if err4 != nil {
+			return err4
+		}
+		ManagerPtrVal3, err4 := func(UserPtrVal3 *User) (*Manager, error) {
+			boolVal, err3 := service.IsVip(UserPtrVal3)
+			if err3 != nil {
+				return nil, err3
+			}
+			if boolVal {
+				ManagerPtrVal2, err3 := func(UserPtrVal *User) (*Manager, error) {
+					ManagerPtrVal, err := service.AssignPersonalManager(UserPtrVal)
+					if err != nil {
+						return ManagerPtrVal, err
+					}
+					return ManagerPtrVal, nil
+				}(UserPtrVal3)
+				if err3 != nil {
+					return ManagerPtrVal2, err3
+				}
+				return ManagerPtrVal2, nil
+			}
+			err3 = func(UserPtrVal2 *User) error {
+				err2 := service.AssignQueue(UserPtrVal2)
+				if err2 != nil {
+					return err2
+				}
+				return nil
+			}(UserPtrVal3)
+			if err3 != nil {
+				return nil, err3
+			}
+			return nil, nil
+		}(UserPtrVal4)
+		if err4 != nil {
+			return err4
+		}
+		err4 = service.Notify(UserPtrVal4, ManagerPtrVal3)
+		if err4 != nil {
+			return err4
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{assignPersonalManagerFieldFunc: assignPersonalManager(), assignQueueFieldFunc: assignQueue(), isVipFieldFunc: isVip(), notifyFieldFunc: notify(), step1FieldFunc: step1()}
+}
+
+type AService interface {
+	AssignPersonalManager(user *User) (*Manager, error)
+	AssignQueue(user *User) error
+	IsVip(user *User) (bool, error)
+	Notify(user *User, manager *Manager) error
+	Step1(id string) (*User, error)
+}
+type AImpl struct {
+	assignPersonalManagerFieldFunc func(user *User) (*Manager, error)
+	assignQueueFieldFunc           func(user *User) error
+	isVipFieldFunc                 func(user *User) (bool, error)
+	notifyFieldFunc                func(user *User, manager *Manager) error
+	step1FieldFunc                 func(id string) (*User, error)
+}
+type AFunc func(stringVal string) error
+
+func (a *AImpl) AssignPersonalManager(user *User) (*Manager, error) {
+	return a.assignPersonalManagerFieldFunc(user)
+}
+func (a *AImpl) AssignQueue(user *User) error              { return a.assignQueueFieldFunc(user) }
+func (a *AImpl) IsVip(user *User) (bool, error)            { return a.isVipFieldFunc(user) }
+func (a *AImpl) Notify(user *User, manager *Manager) error { return a.notifyFieldFunc(user, manager) }
+func (a *AImpl) Step1(id string) (*User, error)            { return a.step1FieldFunc(id) }
@@ -0,0 +1,15 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.If(effe.Step(isVip),
+			effe.Step(addBonus),
+		),
+	)
+	return nil
+}
@@ -0,0 +1,23 @@
+package main
+
+type User struct {
+	ID string
+}
+
+func step1() func(id string) (*User, error) {
+	return func(id string) (*User, error) {
+		return &User{ID: id}, nil
+	}
+}
+
+func isVip() func(user *User) bool {
+	return func(user *User) bool {
+		return true
+	}
+}
+
+func addBonus() func(user *User) error {
+	return func(user *User) error {
+		return nil
+	}
+}
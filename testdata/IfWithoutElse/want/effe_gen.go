@@ -0,0 +1,54 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+func A(service AService) AFunc {
+	return func(stringVal string) error {
+		UserPtrVal3, err3 := service.Step1(stringVal)
+		if err3 != nil {
+			return err3
+		}
+		err3 = func(UserPtrVal2 *User) error {
+			boolVal := service.IsVip(UserPtrVal2)
+			if boolVal {
+				err2 := func(UserPtrVal *User) error {
+					err := service.AddBonus(UserPtrVal)
+					if err != nil {
+						return err
+					}
+					return nil
+				}(UserPtrVal2)
+				if err2 != nil {
+					return err2
+				}
+				return nil
+			}
+			return nil
+		}(UserPtrVal3)
+		if err3 != nil {
+			return err3
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{addBonusFieldFunc: addBonus(), isVipFieldFunc: isVip(), step1FieldFunc: step1()}
+}
+
+type AService interface {
+	AddBonus(user *User) error
+	IsVip(user *User) bool
+	Step1(id string) (*User, error)
+}
+type AImpl struct {
+	addBonusFieldFunc func(user *User) error
+	isVipFieldFunc    func(user *User) bool
+	step1FieldFunc    func(id string) (*User, error)
+}
+type AFunc func(stringVal string) error
+
+func (a *AImpl) AddBonus(user *User) error      { return a.addBonusFieldFunc(user) }
+func (a *AImpl) IsVip(user *User) bool          { return a.isVipFieldFunc(user) }
+func (a *AImpl) Step1(id string) (*User, error) { return a.step1FieldFunc(id) }
@@ -0,0 +1,115 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+func A(service AService) AFunc {
+	return func(ctx4 context.Context) error {
+		OrderPtrVal4, err6 := service.LoadOrder(ctx4)
+		if err6 != nil {
+			return err6
+		}
+		UserPtrVal5, UserPtrVal6, err6 := func(ctx3 context.Context, OrderPtrVal3 *Order) (*User, *User, error) {
+			ctx3, cancel := context.WithCancel(ctx3)
+			defer cancel()
+			var (
+				wg          sync.WaitGroup
+				UserPtrVal3 *User
+				UserPtrVal4 *User
+				errOnce     sync.Once
+				err3        error
+			)
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				var err4 error
+				UserPtrVal4, err4 = func(ctx context.Context, OrderPtrVal *Order) (*User, error) {
+					UserPtrVal, err := service.LoadUser(ctx, OrderPtrVal)
+					if err != nil {
+						return UserPtrVal, err
+					}
+					return UserPtrVal, nil
+				}(ctx3, OrderPtrVal3)
+				if err4 != nil {
+					errOnce.Do(func() {
+						err3 = err4
+						cancel()
+					})
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				var err5 error
+				UserPtrVal3, err5 = func(ctx2 context.Context, OrderPtrVal2 *Order) (*User, error) {
+					UserPtrVal2, err2 := service.LoadDriver(ctx2, OrderPtrVal2)
+					if err2 != nil {
+						return UserPtrVal2, err2
+					}
+					return UserPtrVal2, nil
+				}(ctx3, OrderPtrVal3)
+				if err5 != nil {
+					errOnce.Do(func() {
+						err3 = err5
+						cancel()
+					})
+				}
+			}()
+			wg.Wait()
+			if err3 != nil {
+				return UserPtrVal3, UserPtrVal4, err3
+			}
+			return UserPtrVal3, UserPtrVal4, nil
+		}(ctx4, OrderPtrVal4)
+		if err6 != nil {
+			return err6
+		}
+		RidePtrVal, err6 := service.CreateRide(OrderPtrVal4, UserPtrVal6, UserPtrVal5)
+		if err6 != nil {
+			return err6
+		}
+		err6 = service.NotifyDriver(UserPtrVal5, RidePtrVal)
+		if err6 != nil {
+			return err6
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{createRideFieldFunc: createRide(), loadDriverFieldFunc: loadDriver(), loadOrderFieldFunc: loadOrder(), loadUserFieldFunc: loadUser(), notifyDriverFieldFunc: notifyDriver()}
+}
+
+type AService interface {
+	CreateRide(order *Order, passenger, driver *User) (*Ride, error)
+	LoadDriver(ctx context.Context, order *Order) (*User, error)
+	LoadOrder(ctx context.Context) (*Order, error)
+	LoadUser(ctx context.Context, order *Order) (*User, error)
+	NotifyDriver(driver *User, ride *Ride) error
+}
+type AImpl struct {
+	createRideFieldFunc   func(order *Order, passenger, driver *User) (*Ride, error)
+	loadDriverFieldFunc   func(ctx context.Context, order *Order) (*User, error)
+	loadOrderFieldFunc    func(ctx context.Context) (*Order, error)
+	loadUserFieldFunc     func(ctx context.Context, order *Order) (*User, error)
+	notifyDriverFieldFunc func(driver *User, ride *Ride) error
+}
+type AFunc func(ctx4 context.Context) error
+
+func (a *AImpl) CreateRide(order *Order, passenger, driver *User) (*Ride, error) {
+	return a.createRideFieldFunc(order, passenger, driver)
+}
+func (a *AImpl) LoadDriver(ctx context.Context, order *Order) (*User, error) {
+	return a.loadDriverFieldFunc(ctx, order)
+}
+func (a *AImpl) LoadOrder(ctx context.Context) (*Order, error) { return a.loadOrderFieldFunc(ctx) }
+func (a *AImpl) LoadUser(ctx context.Context, order *Order) (*User, error) {
+	return a.loadUserFieldFunc(ctx, order)
+}
+func (a *AImpl) NotifyDriver(driver *User, ride *Ride) error {
+	return a.notifyDriverFieldFunc(driver, ride)
+}
@@ -0,0 +1,16 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(loadOrder),
+		effe.Step(loadUser, effe.As("passenger")),
+		effe.Step(loadDriver, effe.As("driver")),
+		effe.Step(createRide),
+		effe.Step(notifyDriver),
+	)
+	return nil
+}
@@ -0,0 +1,14 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(loadUser, effe.As("passenger")),
+		effe.Step(loadUser, effe.As("driver")),
+		effe.Step(createRide),
+	)
+	return nil
+}
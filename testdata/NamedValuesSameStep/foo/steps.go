@@ -0,0 +1,22 @@
+package main
+
+type User struct {
+	ID string
+}
+
+type Ride struct {
+	Passenger *User
+	Driver    *User
+}
+
+func loadUser() func(id string) (*User, error) {
+	return func(id string) (*User, error) {
+		return &User{ID: id}, nil
+	}
+}
+
+func createRide() func(passenger *User, driver *User) (*Ride, error) {
+	return func(passenger *User, driver *User) (*Ride, error) {
+		return &Ride{Passenger: passenger, Driver: driver}, nil
+	}
+}
@@ -0,0 +1,41 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+func A(service AService) AFunc {
+	return func(stringVal string) (*Ride, error) {
+		UserPtrVal, err := service.LoadUser(stringVal)
+		if err != nil {
+			return nil, err
+		}
+		UserPtrVal2, err := service.LoadUser(stringVal)
+		if err != nil {
+			return nil, err
+		}
+		RidePtrVal, err := service.CreateRide(UserPtrVal, UserPtrVal2)
+		if err != nil {
+			return RidePtrVal, err
+		}
+		return RidePtrVal, nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{createRideFieldFunc: createRide(), loadUserFieldFunc: loadUser()}
+}
+
+type AService interface {
+	CreateRide(passenger *User, driver *User) (*Ride, error)
+	LoadUser(id string) (*User, error)
+}
+type AImpl struct {
+	createRideFieldFunc func(passenger *User, driver *User) (*Ride, error)
+	loadUserFieldFunc   func(id string) (*User, error)
+}
+type AFunc func(stringVal string) (*Ride, error)
+
+func (a *AImpl) CreateRide(passenger *User, driver *User) (*Ride, error) {
+	return a.createRideFieldFunc(passenger, driver)
+}
+func (a *AImpl) LoadUser(id string) (*User, error) { return a.loadUserFieldFunc(id) }
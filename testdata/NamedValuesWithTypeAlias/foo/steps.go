@@ -0,0 +1,49 @@
+package main
+
+import "context"
+
+type Order struct {
+	ID string
+}
+
+type User struct {
+	Name string
+}
+
+// Driver is an alias, so arguments with this type take values with type *User
+type Driver = User
+
+type Ride struct {
+	Passenger *User
+	Driver    *User
+}
+
+func loadOrder() func(ctx context.Context) (*Order, error) {
+	return func(ctx context.Context) (*Order, error) {
+		return &Order{}, nil
+	}
+}
+
+func loadUser() func(ctx context.Context, order *Order) (*User, error) {
+	return func(ctx context.Context, order *Order) (*User, error) {
+		return &User{}, nil
+	}
+}
+
+func loadDriver() func(ctx context.Context, order *Order) (*User, error) {
+	return func(ctx context.Context, order *Order) (*User, error) {
+		return &User{}, nil
+	}
+}
+
+func createRide() func(order *Order, passenger, driver *User) (*Ride, error) {
+	return func(order *Order, passenger, driver *User) (*Ride, error) {
+		return &Ride{Passenger: passenger, Driver: driver}, nil
+	}
+}
+
+func notifyDriver() func(driver *Driver, ride *Ride) error {
+	return func(driver *Driver, ride *Ride) error {
+		return nil
+	}
+}
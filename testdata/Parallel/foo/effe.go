@@ -0,0 +1,18 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.Parallel(
+			effe.Step(fetchUser),
+			effe.Step(fetchPrice),
+			effe.Step(notify),
+		),
+		effe.Step(step2),
+	)
+	return nil
+}
@@ -0,0 +1,115 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"example.com/foo"
+	"sync"
+)
+
+func A(service AService) AFunc {
+	return func(ctx4 context.Context) error {
+		OrderPtrVal5, err6 := service.Step1(ctx4)
+		if err6 != nil {
+			return err6
+		}
+		PriceVal3, UserPtrVal3, err6 := func(ctx3 context.Context, OrderPtrVal4 *Order) (Price, *User, error) {
+			ctx3, cancel := context.WithCancel(ctx3)
+			defer cancel()
+			var (
+				wg          sync.WaitGroup
+				PriceVal2   Price
+				UserPtrVal2 *User
+				errOnce     sync.Once
+				err3        error
+			)
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				var err4 error
+				UserPtrVal2, err4 = func(ctx context.Context, OrderPtrVal *Order) (*User, error) {
+					UserPtrVal, err := service.FetchUser(ctx, OrderPtrVal)
+					if err != nil {
+						return UserPtrVal, err
+					}
+					return UserPtrVal, nil
+				}(ctx3, OrderPtrVal4)
+				if err4 != nil {
+					errOnce.Do(func() {
+						err3 = err4
+						cancel()
+					})
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				var err5 error
+				PriceVal2, err5 = func(ctx2 context.Context, OrderPtrVal2 *Order) (Price, error) {
+					PriceVal, err2 := service.FetchPrice(ctx2, OrderPtrVal2)
+					if err2 != nil {
+						return PriceVal, err2
+					}
+					return PriceVal, nil
+				}(ctx3, OrderPtrVal4)
+				if err5 != nil {
+					errOnce.Do(func() {
+						err3 = err5
+						cancel()
+					})
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				func(OrderPtrVal3 *Order) {
+					service.Notify(OrderPtrVal3)
+					return
+				}(OrderPtrVal4)
+			}()
+			wg.Wait()
+			if err3 != nil {
+				return PriceVal2, UserPtrVal2, err3
+			}
+			return PriceVal2, UserPtrVal2, nil
+		}(ctx4, OrderPtrVal5)
+		if err6 != nil {
+			return err6
+		}
+		err6 = service.Step2(UserPtrVal3, PriceVal3)
+		if err6 != nil {
+			return err6
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{fetchPriceFieldFunc: fetchPrice(), fetchUserFieldFunc: fetchUser(), notifyFieldFunc: notify(), step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+
+type AService interface {
+	FetchPrice(ctx context.Context, order *Order) (Price, error)
+	FetchUser(ctx context.Context, order *Order) (*User, error)
+	Notify(order *Order)
+	Step1(ctx context.Context) (*Order, error)
+	Step2(user *User, price Price) error
+}
+type AImpl struct {
+	fetchPriceFieldFunc func(ctx context.Context, order *Order) (Price, error)
+	fetchUserFieldFunc  func(ctx context.Context, order *Order) (*User, error)
+	notifyFieldFunc     func(order *Order)
+	step1FieldFunc      func(ctx context.Context) (*Order, error)
+	step2FieldFunc      func(user *User, price Price) error
+}
+type AFunc func(ctx4 context.Context) error
+
+func (a *AImpl) FetchPrice(ctx context.Context, order *Order) (Price, error) {
+	return a.fetchPriceFieldFunc(ctx, order)
+}
+func (a *AImpl) FetchUser(ctx context.Context, order *Order) (*User, error) {
+	return a.fetchUserFieldFunc(ctx, order)
+}
+func (a *AImpl) Notify(order *Order)                       { a.notifyFieldFunc(order) }
+func (a *AImpl) Step1(ctx context.Context) (*Order, error) { return a.step1FieldFunc(ctx) }
+func (a *AImpl) Step2(user *User, price Price) error       { return a.step2FieldFunc(user, price) }
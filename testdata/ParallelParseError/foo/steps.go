@@ -0,0 +1,7 @@
+package main
+
+func step1() func() error {
+	return func() error {
+		return nil
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+type User struct {
+	Name string
+}
+
+func step1() func() (*User, error) {
+	return func() (*User, error) {
+		return &User{}, nil
+	}
+}
+
+func step2() func(*User) error {
+	return func(user *User) error {
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"time"
+)
+
+func A(service AService) AFunc {
+	return func(ctx3 context.Context) error {
+		PaymentPtrVal3, err3 := service.Step1(ctx3)
+		if err3 != nil {
+			return err3
+		}
+		ReceiptPtrVal3, err3 := func(ctx2 context.Context, PaymentPtrVal2 *Payment) (*Receipt, error) {
+			var (
+				ReceiptPtrVal2 *Receipt
+				err2           error
+			)
+			attempts := 3
+			backoff := 100 * time.Millisecond
+			for attempt := 1; ; attempt++ {
+				ReceiptPtrVal2, err2 = func(ctx context.Context, PaymentPtrVal *Payment) (*Receipt, error) {
+					ReceiptPtrVal, err := service.CallPSP(ctx, PaymentPtrVal)
+					if err != nil {
+						return ReceiptPtrVal, err
+					}
+					return ReceiptPtrVal, nil
+				}(ctx2, PaymentPtrVal2)
+				if err2 == nil || attempt >= attempts || !service.IsTemporaryErr(err2) {
+					break
+				}
+				select {
+				case <-ctx2.Done():
+					return ReceiptPtrVal2, ctx2.Err()
+				case <-time.After(backoff):
+				}
+				backoff *= 2
+			}
+			if err2 != nil {
+				return ReceiptPtrVal2, err2
+			}
+			return ReceiptPtrVal2, nil
+		}(ctx3, PaymentPtrVal3)
+		if err3 != nil {
+			return err3
+		}
+		err3 = service.Step2(ReceiptPtrVal3)
+		if err3 != nil {
+			return err3
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{callPSPFieldFunc: callPSP(), isTemporaryErrFieldFunc: isTemporaryErr(), step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+
+type AService interface {
+	CallPSP(ctx context.Context, payment *Payment) (*Receipt, error)
+	IsTemporaryErr(err error) bool
+	Step1(ctx context.Context) (*Payment, error)
+	Step2(receipt *Receipt) error
+}
+type AImpl struct {
+	callPSPFieldFunc        func(ctx context.Context, payment *Payment) (*Receipt, error)
+	isTemporaryErrFieldFunc func(err error) bool
+	step1FieldFunc          func(ctx context.Context) (*Payment, error)
+	step2FieldFunc          func(receipt *Receipt) error
+}
+type AFunc func(ctx3 context.Context) error
+
+func (a *AImpl) CallPSP(ctx context.Context, payment *Payment) (*Receipt, error) {
+	return a.callPSPFieldFunc(ctx, payment)
+}
+func (a *AImpl) IsTemporaryErr(err error) bool               { return a.isTemporaryErrFieldFunc(err) }
+func (a *AImpl) Step1(ctx context.Context) (*Payment, error) { return a.step1FieldFunc(ctx) }
+func (a *AImpl) Step2(receipt *Receipt) error                { return a.step2FieldFunc(receipt) }
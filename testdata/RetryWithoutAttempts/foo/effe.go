@@ -0,0 +1,12 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Retry(effe.Step(step1), effe.RetryIf(isTemporaryErr)),
+	)
+	return nil
+}
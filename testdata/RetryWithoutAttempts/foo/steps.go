@@ -0,0 +1,13 @@
+package main
+
+func step1() func() error {
+	return func() error {
+		return nil
+	}
+}
+
+func isTemporaryErr() func(err error) bool {
+	return func(err error) bool {
+		return true
+	}
+}
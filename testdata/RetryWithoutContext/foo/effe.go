@@ -0,0 +1,16 @@
+// +build effeinject
+
+package main
+
+import (
+	"time"
+
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Retry(effe.Step(step1), effe.Attempts(maxAttempts), effe.Backoff(time.Second)),
+	)
+	return nil
+}
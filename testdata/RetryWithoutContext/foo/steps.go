@@ -0,0 +1,9 @@
+package main
+
+const maxAttempts = 5
+
+func step1() func(id string) error {
+	return func(id string) error {
+		return nil
+	}
+}
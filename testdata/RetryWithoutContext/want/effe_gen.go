@@ -0,0 +1,54 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"time"
+)
+
+func A(service AService) AFunc {
+	return func(stringVal3 string) error {
+		err3 := func(stringVal2 string) error {
+			var err2 error
+			attempts := maxAttempts
+			backoff := time.Second
+			for attempt := 1; ; attempt++ {
+				err2 = func(stringVal string) error {
+					err := service.Step1(stringVal)
+					if err != nil {
+						return err
+					}
+					return nil
+				}(stringVal2)
+				if err2 == nil || attempt >= attempts {
+					break
+				}
+				time.Sleep(backoff)
+				backoff *= 2
+			}
+			if err2 != nil {
+				return err2
+			}
+			return nil
+		}(stringVal3)
+		if err3 != nil {
+			return err3
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{step1FieldFunc: step1()}
+}
+
+type AService interface {
+	Step1(id string) error
+}
+type AImpl struct {
+	step1FieldFunc func(id string) error
+}
+type AFunc func(stringVal3 string) error
+
+func (a *AImpl) Step1(id string) error { return a.step1FieldFunc(id) }
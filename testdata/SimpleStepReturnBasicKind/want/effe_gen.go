@@ -4,6 +4,10 @@
 
 package main
 
+import (
+	"net/http"
+)
+
 func A(service AService) AFunc {
 	return func() (converter, []http.Request, []*string, []string, error) {
 		err := service.Step1()
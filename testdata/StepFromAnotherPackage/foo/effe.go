@@ -0,0 +1,17 @@
+// +build effeinject
+
+package main
+
+import (
+	"example.com/payments"
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(buildCharge),
+		effe.Step(payments.ChargeStep),
+		effe.Step(saveReceipt),
+	)
+	return nil
+}
@@ -0,0 +1,15 @@
+package main
+
+import "example.com/payments"
+
+func buildCharge() func(orderID string) (payments.Charge, error) {
+	return func(orderID string) (payments.Charge, error) {
+		return payments.Charge{}, nil
+	}
+}
+
+func saveReceipt() func(receipt *payments.Receipt) error {
+	return func(receipt *payments.Receipt) error {
+		return nil
+	}
+}
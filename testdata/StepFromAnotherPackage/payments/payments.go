@@ -0,0 +1,19 @@
+package payments
+
+import "context"
+
+type Client struct{}
+
+type Charge struct {
+	Amount int
+}
+
+type Receipt struct {
+	ID string
+}
+
+func ChargeStep(client *Client) func(ctx context.Context, charge Charge) (*Receipt, error) {
+	return func(ctx context.Context, charge Charge) (*Receipt, error) {
+		return &Receipt{}, nil
+	}
+}
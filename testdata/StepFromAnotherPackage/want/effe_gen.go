@@ -0,0 +1,51 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"example.com/payments"
+)
+
+func A(service AService) AFunc {
+	return func(ctx context.Context, stringVal string) error {
+		chargeVal, err := service.BuildCharge(stringVal)
+		if err != nil {
+			return err
+		}
+		receiptPtrVal, err := service.PaymentsChargeStep(ctx, chargeVal)
+		if err != nil {
+			return err
+		}
+		err = service.SaveReceipt(receiptPtrVal)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+func NewAImpl(client *payments.Client) *AImpl {
+	return &AImpl{buildChargeFieldFunc: buildCharge(), paymentsChargeStepFieldFunc: payments.ChargeStep(client), saveReceiptFieldFunc: saveReceipt()}
+}
+
+type AService interface {
+	BuildCharge(orderID string) (payments.Charge, error)
+	PaymentsChargeStep(ctx context.Context, charge payments.Charge) (*payments.Receipt, error)
+	SaveReceipt(receipt *payments.Receipt) error
+}
+type AImpl struct {
+	buildChargeFieldFunc        func(orderID string) (payments.Charge, error)
+	paymentsChargeStepFieldFunc func(ctx context.Context, charge payments.Charge) (*payments.Receipt, error)
+	saveReceiptFieldFunc        func(receipt *payments.Receipt) error
+}
+type AFunc func(ctx context.Context, stringVal string) error
+
+func (a *AImpl) BuildCharge(orderID string) (payments.Charge, error) {
+	return a.buildChargeFieldFunc(orderID)
+}
+func (a *AImpl) PaymentsChargeStep(ctx context.Context, charge payments.Charge) (*payments.Receipt, error) {
+	return a.paymentsChargeStepFieldFunc(ctx, charge)
+}
+func (a *AImpl) SaveReceipt(receipt *payments.Receipt) error { return a.saveReceiptFieldFunc(receipt) }
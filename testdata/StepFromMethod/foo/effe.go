@@ -0,0 +1,14 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step((*UserRepository).Load),
+		effe.Step(PriceCalculator.Calculate),
+		effe.Step((*PaymentService).Charge),
+	)
+	return nil
+}
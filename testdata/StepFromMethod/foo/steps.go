@@ -0,0 +1,35 @@
+package main
+
+import "context"
+
+type User struct {
+	ID string
+}
+
+type Price struct {
+	Amount int
+}
+
+type Receipt struct {
+	ID string
+}
+
+type UserRepository struct{}
+
+func (r *UserRepository) Load(ctx context.Context, id string) (*User, error) {
+	return &User{ID: id}, nil
+}
+
+type PriceCalculator struct {
+	Currency string
+}
+
+func (c PriceCalculator) Calculate(user *User) Price {
+	return Price{}
+}
+
+type PaymentService struct{}
+
+func (s *PaymentService) Charge(ctx context.Context, user *User, price Price) (*Receipt, error) {
+	return &Receipt{}, nil
+}
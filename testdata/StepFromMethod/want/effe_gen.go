@@ -0,0 +1,50 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"example.com/foo"
+)
+
+func A(service AService) AFunc {
+	return func(ctx context.Context, stringVal string) (*Receipt, error) {
+		UserPtrVal, err := service.UserRepositoryLoad(ctx, stringVal)
+		if err != nil {
+			return nil, err
+		}
+		PriceVal := service.PriceCalculatorCalculate(UserPtrVal)
+		ReceiptPtrVal, err := service.PaymentServiceCharge(ctx, UserPtrVal, PriceVal)
+		if err != nil {
+			return ReceiptPtrVal, err
+		}
+		return ReceiptPtrVal, nil
+	}
+}
+func NewAImpl(paymentService *PaymentService, userRepository *UserRepository, priceCalculator PriceCalculator) *AImpl {
+	return &AImpl{paymentServiceChargeFieldFunc: paymentService.Charge, priceCalculatorCalculateFieldFunc: priceCalculator.Calculate, userRepositoryLoadFieldFunc: userRepository.Load}
+}
+
+type AService interface {
+	PaymentServiceCharge(ctx context.Context, user *User, price Price) (*Receipt, error)
+	PriceCalculatorCalculate(user *User) Price
+	UserRepositoryLoad(ctx context.Context, id string) (*User, error)
+}
+type AImpl struct {
+	paymentServiceChargeFieldFunc     func(ctx context.Context, user *User, price Price) (*Receipt, error)
+	priceCalculatorCalculateFieldFunc func(user *User) Price
+	userRepositoryLoadFieldFunc       func(ctx context.Context, id string) (*User, error)
+}
+type AFunc func(ctx context.Context, stringVal string) (*Receipt, error)
+
+func (a *AImpl) PaymentServiceCharge(ctx context.Context, user *User, price Price) (*Receipt, error) {
+	return a.paymentServiceChargeFieldFunc(ctx, user, price)
+}
+func (a *AImpl) PriceCalculatorCalculate(user *User) Price {
+	return a.priceCalculatorCalculateFieldFunc(user)
+}
+func (a *AImpl) UserRepositoryLoad(ctx context.Context, id string) (*User, error) {
+	return a.userRepositoryLoadFieldFunc(ctx, id)
+}
@@ -0,0 +1,38 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"net/http"
+)
+
+func A(service AService) AFunc {
+	return func(requestPtrVal *http.Request, responseWriterVal http.ResponseWriter) error {
+		err := service.Authorize(requestPtrVal)
+		if err != nil {
+			return err
+		}
+		service.Serve(responseWriterVal, requestPtrVal)
+		return nil
+	}
+}
+func NewAImpl(mux *http.ServeMux) *AImpl {
+	return &AImpl{authorizeFieldFunc: authorize(), serveFieldFunc: serve(mux)}
+}
+
+type AService interface {
+	Authorize(r *http.Request) error
+	Serve(arg1 http.ResponseWriter, arg2 *http.Request)
+}
+type AImpl struct {
+	authorizeFieldFunc func(r *http.Request) error
+	serveFieldFunc     func(arg1 http.ResponseWriter, arg2 *http.Request)
+}
+type AFunc func(requestPtrVal *http.Request, responseWriterVal http.ResponseWriter) error
+
+func (a *AImpl) Authorize(r *http.Request) error { return a.authorizeFieldFunc(r) }
+func (a *AImpl) Serve(arg1 http.ResponseWriter, arg2 *http.Request) {
+	a.serveFieldFunc(arg1, arg2)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+type User struct {
+	Name string
+}
+
+type Charge struct {
+	Amount int
+}
+
+type Notifier func(ctx context.Context, user *User, charge *Charge) error
+
+type userLoader struct {
+	client *http.Client
+}
+
+func (l *userLoader) load(ctx context.Context) (*User, error) {
+	return &User{}, nil
+}
+
+func loadUser(client *http.Client) func(ctx context.Context) (*User, error) {
+	loader := &userLoader{client: client}
+	return loader.load
+}
+
+func newCharger(amount int) func(context.Context, *User) (*Charge, error) {
+	return func(ctx context.Context, user *User) (*Charge, error) {
+		return &Charge{Amount: amount}, nil
+	}
+}
+
+func chargeUser() func(context.Context, *User) (*Charge, error) {
+	amount := 100
+	if amount <= 0 {
+		return nil
+	}
+	return newCharger(amount)
+}
+
+func notifyUser(client *http.Client) Notifier {
+	return func(ctx context.Context, user *User, charge *Charge) error {
+		return nil
+	}
+}
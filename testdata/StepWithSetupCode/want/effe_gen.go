@@ -0,0 +1,51 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+func A(service AService) AFunc {
+	return func(ctx context.Context) error {
+		UserPtrVal, err := service.LoadUser(ctx)
+		if err != nil {
+			return err
+		}
+		ChargePtrVal, err := service.ChargeUser(ctx, UserPtrVal)
+		if err != nil {
+			return err
+		}
+		err = service.NotifyUser(ctx, UserPtrVal, ChargePtrVal)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+func NewAImpl(client *http.Client) *AImpl {
+	return &AImpl{chargeUserFieldFunc: chargeUser(), loadUserFieldFunc: loadUser(client), notifyUserFieldFunc: notifyUser(client)}
+}
+
+type AService interface {
+	ChargeUser(arg1 context.Context, arg2 *User) (*Charge, error)
+	LoadUser(ctx context.Context) (*User, error)
+	NotifyUser(ctx context.Context, user *User, charge *Charge) error
+}
+type AImpl struct {
+	chargeUserFieldFunc func(arg1 context.Context, arg2 *User) (*Charge, error)
+	loadUserFieldFunc   func(ctx context.Context) (*User, error)
+	notifyUserFieldFunc func(ctx context.Context, user *User, charge *Charge) error
+}
+type AFunc func(ctx context.Context) error
+
+func (a *AImpl) ChargeUser(arg1 context.Context, arg2 *User) (*Charge, error) {
+	return a.chargeUserFieldFunc(arg1, arg2)
+}
+func (a *AImpl) LoadUser(ctx context.Context) (*User, error) { return a.loadUserFieldFunc(ctx) }
+func (a *AImpl) NotifyUser(ctx context.Context, user *User, charge *Charge) error {
+	return a.notifyUserFieldFunc(ctx, user, charge)
+}
@@ -0,0 +1,21 @@
+// +build effeinject
+
+package main
+
+import (
+	"time"
+
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.Timeout(800*time.Millisecond,
+			effe.Step(step2),
+			effe.Step(step3),
+		),
+		effe.Failure(failure),
+	)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"github.com/GettEngineering/effe"
+	"time"
+)
+
+func A(service AService) AFunc {
+	return func(ctx3 context.Context, stringVal string) error {
+		UserPtrVal3, err3 := service.Step1(stringVal)
+		if err3 != nil {
+			err3 = service.Failure(err3)
+			return err3
+		}
+		err3 = func(ctx2 context.Context, UserPtrVal2 *User) error {
+			durationVal := 800 * time.Millisecond
+			ctx2, cancel := context.WithTimeout(ctx2, durationVal)
+			defer cancel()
+			err2 := func(ctx context.Context, UserPtrVal *User) error {
+				DriverPtrVal, err := service.Step2(ctx, UserPtrVal)
+				if err != nil {
+					return err
+				}
+				err = service.Step3(ctx, DriverPtrVal)
+				if err != nil {
+					return err
+				}
+				return nil
+			}(ctx2, UserPtrVal2)
+			if err2 != nil && ctx2.Err() == context.DeadlineExceeded {
+				err2 = &effe.TimeoutError{Name: "A.Step2-Step3 (" + durationVal.String() + ")", Err: err2}
+			}
+			if err2 != nil {
+				return err2
+			}
+			return nil
+		}(ctx3, UserPtrVal3)
+		if err3 != nil {
+			err3 = service.Failure(err3)
+			return err3
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{failureFieldFunc: failure(), step1FieldFunc: step1(), step2FieldFunc: step2(), step3FieldFunc: step3()}
+}
+
+type AService interface {
+	Failure(err error) error
+	Step1(id string) (*User, error)
+	Step2(ctx context.Context, user *User) (*Driver, error)
+	Step3(ctx context.Context, driver *Driver) error
+}
+type AImpl struct {
+	failureFieldFunc func(err error) error
+	step1FieldFunc   func(id string) (*User, error)
+	step2FieldFunc   func(ctx context.Context, user *User) (*Driver, error)
+	step3FieldFunc   func(ctx context.Context, driver *Driver) error
+}
+type AFunc func(ctx3 context.Context, stringVal string) error
+
+func (a *AImpl) Failure(err error) error        { return a.failureFieldFunc(err) }
+func (a *AImpl) Step1(id string) (*User, error) { return a.step1FieldFunc(id) }
+func (a *AImpl) Step2(ctx context.Context, user *User) (*Driver, error) {
+	return a.step2FieldFunc(ctx, user)
+}
+func (a *AImpl) Step3(ctx context.Context, driver *Driver) error {
+	return a.step3FieldFunc(ctx, driver)
+}
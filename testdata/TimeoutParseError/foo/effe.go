@@ -0,0 +1,16 @@
+// +build effeinject
+
+package main
+
+import (
+	"time"
+
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Timeout(time.Second),
+	)
+	return nil
+}
@@ -0,0 +1,13 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(loadUser),
+		effe.Step(chargeCustomer),
+	)
+	return nil
+}
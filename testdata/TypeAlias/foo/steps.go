@@ -0,0 +1,24 @@
+package main
+
+type User struct {
+	ID string
+}
+
+// Customer is the same type as User
+type Customer = User
+
+type Receipt struct {
+	Amount int
+}
+
+func loadUser() func(id string) (*User, error) {
+	return func(id string) (*User, error) {
+		return &User{ID: id}, nil
+	}
+}
+
+func chargeCustomer() func(customer *Customer) (Receipt, error) {
+	return func(customer *Customer) (Receipt, error) {
+		return Receipt{}, nil
+	}
+}
@@ -0,0 +1,41 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"example.com/foo"
+)
+
+func A(service AService) AFunc {
+	return func(stringVal string) (Receipt, error) {
+		UserPtrVal, err := service.LoadUser(stringVal)
+		if err != nil {
+			return Receipt{}, err
+		}
+		ReceiptVal, err := service.ChargeCustomer(UserPtrVal)
+		if err != nil {
+			return ReceiptVal, err
+		}
+		return ReceiptVal, nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{chargeCustomerFieldFunc: chargeCustomer(), loadUserFieldFunc: loadUser()}
+}
+
+type AService interface {
+	ChargeCustomer(customer *Customer) (Receipt, error)
+	LoadUser(id string) (*User, error)
+}
+type AImpl struct {
+	chargeCustomerFieldFunc func(customer *Customer) (Receipt, error)
+	loadUserFieldFunc       func(id string) (*User, error)
+}
+type AFunc func(stringVal string) (Receipt, error)
+
+func (a *AImpl) ChargeCustomer(customer *Customer) (Receipt, error) {
+	return a.chargeCustomerFieldFunc(customer)
+}
+func (a *AImpl) LoadUser(id string) (*User, error) { return a.loadUserFieldFunc(id) }
@@ -0,0 +1,61 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+func A(service AService) AFunc {
+	return func() error {
+		UserPtrVal2, err2 := service.Step1()
+		if err2 != nil {
+			return err2
+		}
+		err2 = func(UserPtrVal *User) error {
+			ConnPtrVal := service.AcquireConn()
+			defer service.ReleaseConn(ConnPtrVal)
+			err := service.Step2(ConnPtrVal, UserPtrVal)
+			if err != nil {
+				err = service.FailureStep(err)
+				return err
+			}
+			err = service.Step3(ConnPtrVal)
+			if err != nil {
+				err = service.FailureStep(err)
+				return err
+			}
+			return nil
+		}(UserPtrVal2)
+		if err2 != nil {
+			return err2
+		}
+		return nil
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{acquireConnFieldFunc: acquireConn(), failureStepFieldFunc: failureStep(), releaseConnFieldFunc: releaseConn(), step1FieldFunc: step1(), step2FieldFunc: step2(), step3FieldFunc: step3()}
+}
+
+type AService interface {
+	AcquireConn() *Conn
+	FailureStep(err error) error
+	ReleaseConn(conn *Conn)
+	Step1() (*User, error)
+	Step2(conn *Conn, user *User) error
+	Step3(conn *Conn) error
+}
+type AImpl struct {
+	acquireConnFieldFunc func() *Conn
+	failureStepFieldFunc func(err error) error
+	releaseConnFieldFunc func(conn *Conn)
+	step1FieldFunc       func() (*User, error)
+	step2FieldFunc       func(conn *Conn, user *User) error
+	step3FieldFunc       func(conn *Conn) error
+}
+type AFunc func() error
+
+func (a *AImpl) AcquireConn() *Conn                 { return a.acquireConnFieldFunc() }
+func (a *AImpl) FailureStep(err error) error        { return a.failureStepFieldFunc(err) }
+func (a *AImpl) ReleaseConn(conn *Conn)             { a.releaseConnFieldFunc(conn) }
+func (a *AImpl) Step1() (*User, error)              { return a.step1FieldFunc() }
+func (a *AImpl) Step2(conn *Conn, user *User) error { return a.step2FieldFunc(conn, user) }
+func (a *AImpl) Step3(conn *Conn) error             { return a.step3FieldFunc(conn) }
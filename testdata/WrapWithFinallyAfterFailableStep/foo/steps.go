@@ -0,0 +1,43 @@
+package main
+
+type User struct {
+	ID string
+}
+
+type Conn struct {
+	ID string
+}
+
+func step1() func() (*User, error) {
+	return func() (*User, error) {
+		return &User{}, nil
+	}
+}
+
+func acquireConn() func() (*Conn, error) {
+	return func() (*Conn, error) {
+		return &Conn{}, nil
+	}
+}
+
+func step2() func(conn *Conn, user *User) error {
+	return func(conn *Conn, user *User) error {
+		return nil
+	}
+}
+
+func step3() func(conn *Conn) error {
+	return func(conn *Conn) error {
+		return nil
+	}
+}
+
+func failureStep() func(err error) error {
+	return func(err error) error {
+		return err
+	}
+}
+
+func releaseConn() func(conn *Conn) {
+	return func(conn *Conn) {}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/GettEngineering/effe/drawer"
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	"github.com/GettEngineering/effe/testing"
+	"github.com/GettEngineering/effe/types"
+)
+
+func newDiagramGenerator(d drawer.Drawer) testing.GenerateFunc {
+	gen := generator.NewGenerator(
+		generator.WithSetttings(generator.DefaultSettigs()),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithDrawer(d),
+	)
+	return func(ctx context.Context, wd string, env []string, patterns []string) ([]types.GenerateResult, []error) {
+		return gen.GenerateDiagram(ctx, wd, env, patterns, "graphs")
+	}
+}
+
+func main() {
+	testRoot := os.Args[2]
+	testing.UpdateExpectedOutputs(filepath.Join(testRoot, "mermaid"), newDiagramGenerator(drawer.NewMermaidDrawer()), nil, []string{})
+	testing.UpdateExpectedOutputs(filepath.Join(testRoot, "dot"), newDiagramGenerator(drawer.NewDotDrawer()), nil, []string{})
+	testing.UpdateExpectedOutputs(
+		filepath.Join(testRoot, "plantumltypes"),
+		newDiagramGenerator(drawer.NewDrawer(drawer.WithTypes(), drawer.WithDeps())),
+		nil,
+		[]string{},
+	)
+	for depth, dir := range []string{"plantumlnested0", "plantumlnested1"} {
+		testing.UpdateExpectedOutputs(
+			filepath.Join(testRoot, dir),
+			newDiagramGenerator(drawer.NewDrawer(drawer.WithNestedFlows(depth))),
+			nil,
+			[]string{},
+		)
+	}
+}
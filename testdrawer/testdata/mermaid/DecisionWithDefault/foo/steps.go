@@ -0,0 +1,27 @@
+package main
+
+type Status string
+
+const (
+	StatusNew     Status = "new"
+	StatusPending Status = "pending"
+	StatusDone    Status = "done"
+)
+
+func getStatus() func(id string) Status {
+	return func(id string) Status {
+		return StatusNew
+	}
+}
+
+func notify() func(id string) {
+	return func(id string) {}
+}
+
+func archive() func(id string) {
+	return func(id string) {}
+}
+
+func logUnknownStatus() func(status Status) {
+	return func(status Status) {}
+}
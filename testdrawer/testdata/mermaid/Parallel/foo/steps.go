@@ -0,0 +1,44 @@
+package main
+
+import "context"
+
+type Order struct {
+	ID string
+}
+
+type User struct {
+	Name string
+}
+
+type Price struct {
+	Amount int
+}
+
+func step1() func(ctx context.Context) (*Order, error) {
+	return func(ctx context.Context) (*Order, error) {
+		return &Order{}, nil
+	}
+}
+
+func fetchUser() func(ctx context.Context, order *Order) (*User, error) {
+	return func(ctx context.Context, order *Order) (*User, error) {
+		return &User{}, nil
+	}
+}
+
+func fetchPrice() func(ctx context.Context, order *Order) (Price, error) {
+	return func(ctx context.Context, order *Order) (Price, error) {
+		return Price{}, nil
+	}
+}
+
+func notify() func(order *Order) {
+	return func(order *Order) {
+	}
+}
+
+func step2() func(user *User, price Price) error {
+	return func(user *User, price Price) error {
+		return nil
+	}
+}
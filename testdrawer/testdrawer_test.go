@@ -0,0 +1,43 @@
+package testdrawer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GettEngineering/effe/drawer"
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	eTesting "github.com/GettEngineering/effe/testing"
+	"github.com/GettEngineering/effe/types"
+)
+
+func newDiagramGenerator(d drawer.Drawer) eTesting.GenerateFunc {
+	gen := generator.NewGenerator(
+		generator.WithSetttings(generator.DefaultSettigs()),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithDrawer(d),
+	)
+	return func(ctx context.Context, wd string, env []string, patterns []string) ([]types.GenerateResult, []error) {
+		return gen.GenerateDiagram(ctx, wd, env, patterns, "graphs")
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	eTesting.RunOutputTests(t, newDiagramGenerator(drawer.NewMermaidDrawer()), "testdata/mermaid", nil, []string{})
+}
+
+func TestDot(t *testing.T) {
+	eTesting.RunOutputTests(t, newDiagramGenerator(drawer.NewDotDrawer()), "testdata/dot", nil, []string{})
+}
+
+func TestPlantUMLWithTypes(t *testing.T) {
+	d := drawer.NewDrawer(drawer.WithTypes(), drawer.WithDeps())
+	eTesting.RunOutputTests(t, newDiagramGenerator(d), "testdata/plantumltypes", nil, []string{})
+}
+
+func TestPlantUMLWithNestedFlows(t *testing.T) {
+	for depth, testRoot := range []string{"testdata/plantumlnested0", "testdata/plantumlnested1"} {
+		d := drawer.NewDrawer(drawer.WithNestedFlows(depth))
+		eTesting.RunOutputTests(t, newDiagramGenerator(d), testRoot, nil, []string{})
+	}
+}
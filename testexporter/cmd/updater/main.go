@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+
+	"github.com/GettEngineering/effe/exporter"
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	"github.com/GettEngineering/effe/testing"
+)
+
+func main() {
+	gen := generator.NewGenerator(
+		generator.WithSetttings(generator.DefaultSettigs()),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithExporter(exporter.NewExporter()),
+	)
+	testing.UpdateExpectedOutputs(os.Args[2], gen.GenerateJSON, nil, []string{})
+}
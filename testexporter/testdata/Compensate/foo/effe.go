@@ -0,0 +1,16 @@
+// +build effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(reserveHotel, effe.Compensate(cancelHotel)),
+		effe.Step(chargeCard, effe.Compensate(refundCard)),
+		effe.Step(sendConfirmation),
+	)
+	return nil
+}
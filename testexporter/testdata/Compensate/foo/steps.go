@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+)
+
+type Booking struct {
+	ID string
+}
+
+type Reservation struct {
+	ID string
+}
+
+type Charge struct {
+	ID string
+}
+
+func reserveHotel() func(ctx context.Context, booking *Booking) (*Reservation, error) {
+	return func(ctx context.Context, booking *Booking) (*Reservation, error) {
+		return &Reservation{}, nil
+	}
+}
+
+func cancelHotel() func(ctx context.Context, reservation *Reservation) error {
+	return func(ctx context.Context, reservation *Reservation) error {
+		return nil
+	}
+}
+
+func chargeCard() func(ctx context.Context, booking *Booking) (*Charge, error) {
+	return func(ctx context.Context, booking *Booking) (*Charge, error) {
+		return &Charge{}, nil
+	}
+}
+
+func refundCard() func(charge *Charge) error {
+	return func(charge *Charge) error {
+		return nil
+	}
+}
+
+func sendConfirmation() func(ctx context.Context, reservation *Reservation, charge *Charge) error {
+	return func(ctx context.Context, reservation *Reservation, charge *Charge) error {
+		return nil
+	}
+}
@@ -0,0 +1,17 @@
+// +build effeinject
+
+package main
+
+import "github.com/GettEngineering/effe"
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(getStatus),
+		effe.Decision(new(Status),
+			effe.Case(StatusNew, StatusPending, effe.Step(notify)),
+			effe.Case(StatusDone, effe.Step(archive)),
+			effe.Default(effe.Step(logUnknownStatus)),
+		),
+	)
+	return nil
+}
@@ -0,0 +1,20 @@
+// +build effeinject
+
+package main
+
+import (
+	"time"
+
+	"github.com/GettEngineering/effe"
+)
+
+func A() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.Retry(effe.Step(callPSP), effe.Attempts(3), effe.Backoff(100*time.Millisecond),
+			effe.RetryIf(isTemporaryErr),
+		),
+		effe.Step(step2),
+	)
+	return nil
+}
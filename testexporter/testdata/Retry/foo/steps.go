@@ -0,0 +1,35 @@
+package main
+
+import "context"
+
+type Payment struct {
+	ID string
+}
+
+type Receipt struct {
+	ID string
+}
+
+func step1() func(ctx context.Context) (*Payment, error) {
+	return func(ctx context.Context) (*Payment, error) {
+		return &Payment{}, nil
+	}
+}
+
+func callPSP() func(ctx context.Context, payment *Payment) (*Receipt, error) {
+	return func(ctx context.Context, payment *Payment) (*Receipt, error) {
+		return &Receipt{}, nil
+	}
+}
+
+func isTemporaryErr() func(err error) bool {
+	return func(err error) bool {
+		return true
+	}
+}
+
+func step2() func(receipt *Receipt) error {
+	return func(receipt *Receipt) error {
+		return nil
+	}
+}
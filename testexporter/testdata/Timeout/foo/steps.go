@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GettEngineering/effe"
+)
+
+type User struct {
+	Name string
+}
+
+type Driver struct {
+	Name string
+}
+
+func step1() func(id string) (*User, error) {
+	return func(id string) (*User, error) {
+		return &User{}, nil
+	}
+}
+
+func step2() func(ctx context.Context, user *User) (*Driver, error) {
+	return func(ctx context.Context, user *User) (*Driver, error) {
+		return &Driver{}, nil
+	}
+}
+
+func step3() func(ctx context.Context, driver *Driver) error {
+	return func(ctx context.Context, driver *Driver) error {
+		return nil
+	}
+}
+
+func failure() func(err error) error {
+	return func(err error) error {
+		var timeoutErr *effe.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return timeoutErr.Err
+		}
+		return err
+	}
+}
@@ -0,0 +1,19 @@
+package testexporter
+
+import (
+	"testing"
+
+	"github.com/GettEngineering/effe/exporter"
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	eTesting "github.com/GettEngineering/effe/testing"
+)
+
+func TestExporter(t *testing.T) {
+	gen := generator.NewGenerator(
+		generator.WithSetttings(generator.DefaultSettigs()),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithExporter(exporter.NewExporter()),
+	)
+	eTesting.RunOutputTests(t, gen.GenerateJSON, "testdata", nil, []string{})
+}
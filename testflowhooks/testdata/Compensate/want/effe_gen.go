@@ -0,0 +1,86 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"github.com/GettEngineering/effe"
+	"log"
+)
+
+func A(service AService) AFunc {
+	return func(ctx2 context.Context, BookingPtrVal2 *Booking) error {
+		log.Printf("flow A started")
+		compensations := &effe.Compensations{}
+		err2 := func(ctx context.Context, BookingPtrVal *Booking) error {
+			ReservationPtrVal, err := service.ReserveHotel(ctx, BookingPtrVal)
+			if err != nil {
+				log.Printf("flow A failed in step ReserveHotel: %v", err)
+				return err
+			}
+			{
+				ReservationPtrVal := ReservationPtrVal
+				compensations.Add(func() error {
+					return service.CancelHotel(ctx2, ReservationPtrVal)
+				})
+			}
+			ChargePtrVal, err := service.ChargeCard(ctx, BookingPtrVal)
+			if err != nil {
+				log.Printf("flow A failed in step ChargeCard: %v", err)
+				return err
+			}
+			{
+				ChargePtrVal := ChargePtrVal
+				compensations.Add(func() error {
+					return service.RefundCard(ChargePtrVal)
+				})
+			}
+			err = service.SendConfirmation(ctx, ReservationPtrVal, ChargePtrVal)
+			if err != nil {
+				log.Printf("flow A failed in step SendConfirmation: %v", err)
+				return err
+			}
+			return nil
+		}(ctx2, BookingPtrVal2)
+		if err2 != nil {
+			err2 = compensations.Run(err2)
+		}
+		log.Printf("flow A finished err2: %v", err2)
+		return err2
+	}
+}
+func NewAImpl() *AImpl {
+	return &AImpl{cancelHotelFieldFunc: cancelHotel(), chargeCardFieldFunc: chargeCard(), refundCardFieldFunc: refundCard(), reserveHotelFieldFunc: reserveHotel(), sendConfirmationFieldFunc: sendConfirmation()}
+}
+
+type AService interface {
+	CancelHotel(ctx context.Context, reservation *Reservation) error
+	ChargeCard(ctx context.Context, booking *Booking) (*Charge, error)
+	RefundCard(charge *Charge) error
+	ReserveHotel(ctx context.Context, booking *Booking) (*Reservation, error)
+	SendConfirmation(ctx context.Context, reservation *Reservation, charge *Charge) error
+}
+type AImpl struct {
+	cancelHotelFieldFunc      func(ctx context.Context, reservation *Reservation) error
+	chargeCardFieldFunc       func(ctx context.Context, booking *Booking) (*Charge, error)
+	refundCardFieldFunc       func(charge *Charge) error
+	reserveHotelFieldFunc     func(ctx context.Context, booking *Booking) (*Reservation, error)
+	sendConfirmationFieldFunc func(ctx context.Context, reservation *Reservation, charge *Charge) error
+}
+type AFunc func(ctx2 context.Context, BookingPtrVal2 *Booking) error
+
+func (a *AImpl) CancelHotel(ctx context.Context, reservation *Reservation) error {
+	return a.cancelHotelFieldFunc(ctx, reservation)
+}
+func (a *AImpl) ChargeCard(ctx context.Context, booking *Booking) (*Charge, error) {
+	return a.chargeCardFieldFunc(ctx, booking)
+}
+func (a *AImpl) RefundCard(charge *Charge) error { return a.refundCardFieldFunc(charge) }
+func (a *AImpl) ReserveHotel(ctx context.Context, booking *Booking) (*Reservation, error) {
+	return a.reserveHotelFieldFunc(ctx, booking)
+}
+func (a *AImpl) SendConfirmation(ctx context.Context, reservation *Reservation, charge *Charge) error {
+	return a.sendConfirmationFieldFunc(ctx, reservation, charge)
+}
@@ -0,0 +1,24 @@
+// +build effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe"
+)
+
+func BuildComponent2() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.Step(step2),
+		effe.Step(step3),
+		effe.Failure(failure),
+	)
+	return nil
+}
+
+func BuildComponent3() (User, error) {
+	effe.BuildFlow(
+		effe.Step(step1),
+	)
+	return User{}, nil
+}
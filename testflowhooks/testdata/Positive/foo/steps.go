@@ -0,0 +1,27 @@
+package main
+
+type User struct {
+	Login string
+}
+
+func step1() func(login string) (User, error) {
+	return func(login string) (User, error) {
+		return User{Login: login}, nil
+	}
+}
+
+func step2() func(user User) error {
+	return func(user User) error {
+		return nil
+	}
+}
+
+func step3() func(user User) {
+	return func(user User) {}
+}
+
+func failure() func(err error) error {
+	return func(err error) error {
+		return err
+	}
+}
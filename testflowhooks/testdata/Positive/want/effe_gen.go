@@ -0,0 +1,79 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"example.com/foo"
+	"log"
+)
+
+func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+	return func(stringVal string) error {
+		log.Printf("flow BuildComponent2 started")
+		UserVal, err := service.Step1(stringVal)
+		if err != nil {
+			log.Printf("flow BuildComponent2 failed in step Step1: %v", err)
+			err = service.Failure(err)
+			log.Printf("flow BuildComponent2 finished err: %v", err)
+			return err
+		}
+		err = service.Step2(UserVal)
+		if err != nil {
+			log.Printf("flow BuildComponent2 failed in step Step2: %v", err)
+			err = service.Failure(err)
+			log.Printf("flow BuildComponent2 finished err: %v", err)
+			return err
+		}
+		service.Step3(UserVal)
+		log.Printf("flow BuildComponent2 finished")
+		return nil
+	}
+}
+func BuildComponent3(service BuildComponent3Service) BuildComponent3Func {
+	return func(stringVal string) (User, error) {
+		log.Printf("flow BuildComponent3 started")
+		UserVal, err := service.Step1(stringVal)
+		if err != nil {
+			log.Printf("flow BuildComponent3 failed in step Step1: %v", err)
+			log.Printf("flow BuildComponent3 finished UserVal: %v err: %v", UserVal, err)
+			return UserVal, err
+		}
+		log.Printf("flow BuildComponent3 finished UserVal: %v", UserVal)
+		return UserVal, nil
+	}
+}
+func NewBuildComponent2Impl() *BuildComponent2Impl {
+	return &BuildComponent2Impl{failureFieldFunc: failure(), step1FieldFunc: step1(), step2FieldFunc: step2(), step3FieldFunc: step3()}
+}
+func NewBuildComponent3Impl() *BuildComponent3Impl {
+	return &BuildComponent3Impl{step1FieldFunc: step1()}
+}
+
+type BuildComponent2Service interface {
+	Failure(err error) error
+	Step1(login string) (User, error)
+	Step2(user User) error
+	Step3(user User)
+}
+type BuildComponent2Impl struct {
+	failureFieldFunc func(err error) error
+	step1FieldFunc   func(login string) (User, error)
+	step2FieldFunc   func(user User) error
+	step3FieldFunc   func(user User)
+}
+type BuildComponent2Func func(stringVal string) error
+type BuildComponent3Service interface {
+	Step1(login string) (User, error)
+}
+type BuildComponent3Impl struct {
+	step1FieldFunc func(login string) (User, error)
+}
+type BuildComponent3Func func(stringVal string) (User, error)
+
+func (b *BuildComponent2Impl) Failure(err error) error          { return b.failureFieldFunc(err) }
+func (b *BuildComponent2Impl) Step1(login string) (User, error) { return b.step1FieldFunc(login) }
+func (b *BuildComponent2Impl) Step2(user User) error            { return b.step2FieldFunc(user) }
+func (b *BuildComponent2Impl) Step3(user User)                  { b.step3FieldFunc(user) }
+func (b *BuildComponent3Impl) Step1(login string) (User, error) { return b.step1FieldFunc(login) }
@@ -0,0 +1,97 @@
+package testflowhooks
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	"github.com/GettEngineering/effe/plugin"
+	"github.com/GettEngineering/effe/strategies"
+	eTesting "github.com/GettEngineering/effe/testing"
+)
+
+//go:generate go test -run TestFlowHooks -update
+
+// update regenerates expected results, the plugin is declared in the test,
+// so results can't be updated by a separate command
+var update = flag.Bool("update", false, "update expected results in testdata")
+
+// auditPlugin logs a start and a result of a flow and failures of components
+type auditPlugin struct{}
+
+func (a auditPlugin) buildPrintf(format string, args ...ast.Expr) ast.Stmt {
+	return &ast.ExprStmt{
+		X: &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   ast.NewIdent("log"),
+				Sel: ast.NewIdent("Printf"),
+			},
+			Args: append([]ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", format)}}, args...),
+		},
+	}
+}
+
+func (a auditPlugin) Before(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+	return []ast.Stmt{}
+}
+
+func (a auditPlugin) Success(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+	return []ast.Stmt{}
+}
+
+func (a auditPlugin) Change(ctx plugin.Context, c plugin.ComponentStmt) bool {
+	return false
+}
+
+func (a auditPlugin) BeforeFlow(ctx plugin.Context, name string) []ast.Stmt {
+	return []ast.Stmt{a.buildPrintf("flow " + name + " started")}
+}
+
+func (a auditPlugin) AfterFlow(ctx plugin.Context, name string, fields []*ast.Field) []ast.Stmt {
+	format := "flow " + name + " finished"
+	args := make([]ast.Expr, 0, len(fields))
+	for _, field := range fields {
+		if len(field.Names) > 0 {
+			format += " " + field.Names[0].Name + ": %v"
+			args = append(args, field.Names[0])
+		}
+	}
+	return []ast.Stmt{a.buildPrintf(format, args...)}
+}
+
+func (a auditPlugin) OnFailure(ctx plugin.Context, name string, errVar *ast.Ident) []ast.Stmt {
+	return []ast.Stmt{a.buildPrintf("flow "+plugin.FlowName(ctx)+" failed in step "+name+": %v", errVar)}
+}
+
+func (a auditPlugin) Imports() []string {
+	return []string{
+		"log",
+	}
+}
+
+func NewAuditPlugin() plugin.Plugin {
+	return &auditPlugin{}
+}
+
+func TestFlowHooks(t *testing.T) {
+	settings := generator.DefaultSettigs()
+	strategy := strategies.NewChain(
+		strategies.WithServiceObjectName(settings.LocalInterfaceVarname()),
+		strategies.Use(NewAuditPlugin()),
+	)
+	gen := generator.NewGenerator(
+		generator.WithSetttings(settings),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithStrategy(strategy),
+	)
+
+	if *update {
+		eTesting.UpdateExpectedResult("testdata", gen, map[string][]byte{}, []string{})
+		return
+	}
+	eTesting.RunTests(t, gen, "testdata", map[string][]byte{}, []string{})
+}
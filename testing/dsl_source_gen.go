@@ -13,6 +13,13 @@ var SourceDSL = `// Package effe contains directives for Effe code generation.
 // calls of steps according to strategy.
 package effe
 
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
 // Based type for declaring steps
 type StepFunc interface{}
 
@@ -44,8 +51,17 @@ func BuildFlow(funcs ...StepFunc) interface{} { //nolint:unparam
 // to separate dependencies between steps. You don't need to create big service objects.
 // Effe code generation tool calculates all dependencies for your flow and Effe generates
 // a service object with dependecies automatically.
-// Function return value should be the function which executes here.
+// Function return value should be the function which executes here. The function can contain
+// setup code before the return statement and return a function value or a named function type,
+// for example func step1(client *http.Client) Handler { h := newHandler(client); return h.Handle }.
 // Also, you can call another business flow here. It helps to split and reuse existing business logic.
+// The function can be declared in another package, for example effe.Step(payments.ChargeStep),
+// so a library of steps can be reused by several services.
+// A method expression can be used as a step as well, for example effe.Step((*PaymentService).Charge)
+// or effe.Step(PriceCalculator.Calculate). In this case the receiver is a dependency of the step
+// and the method is the function which executes here.
+// Options configure the step, for example Compensate declares a function which undoes the step
+// and As declares a name of values returned by the step.
 //
 // Examples:
 //
@@ -60,7 +76,49 @@ func BuildFlow(funcs ...StepFunc) interface{} { //nolint:unparam
 //                  effe.Step(BuildMyFirstBusinessFlow),
 //              )
 //          }
-func Step(fn interface{}) StepFunc { //nolint:unparam
+func Step(fn interface{}, options ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// Compensate works the same way as Step, but the function must return nothing or only an error.
+// The function undoes a step: if the flow returns an error, then Effe calls compensations
+// of succeeded steps in reverse order before returning the error.
+// This directive can be used only in Step.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow(){
+//          effe.BuildFlow(
+//              effe.Step(reserveHotel, effe.Compensate(cancelHotel)),
+//              effe.Step(chargeCard, effe.Compensate(refundCard)),
+//              effe.Step(sendConfirmation),
+//          )
+//      }
+func Compensate(fn interface{}) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// As declares a name of values returned by a step. By default Effe passes values between steps
+// by types, so two steps which return the same type overwrite each other. A named value is passed
+// only to arguments with the same name and type.
+// This directive can be used only in Step.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow(){
+//          effe.BuildFlow(
+//              effe.Step(loadPassenger, effe.As("passenger")),
+//              effe.Step(loadDriver, effe.As("driver")),
+//              effe.Step(createRide),
+//          )
+//      }
+//
+//      func createRide() func(passenger *User, driver *User) (*Ride, error) {
+//          ...
+//      }
+func As(name string) StepFunc { //nolint:unparam
 	panicDSLMethodNotFound()
 	return nil
 }
@@ -111,6 +169,58 @@ func Before(fn interface{}) StepFunc { //nolint:unparam
 	return nil
 }
 
+// This directive helps to declare a function which executes after
+// a function declared with Success or Failure directive regardless of the result.
+// The function is deferred right after steps which return its arguments, and
+// it must not return values. These steps and steps before them must not return an error,
+// otherwise the function wouldn't be called when they fail.
+// This directive can be used only in Wrap.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow() {
+//          effe.BuildFlow(
+//              effe.Wrap(effe.Before(acquireConn), effe.Finally(releaseConn),
+//                  effe.Step(step1),
+//                  effe.Step(step2),
+//              ),
+//          )
+//      }
+func Finally(fn interface{}) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// If directive helps to organize branching of our business logic by a predicate.
+// First argument declares the predicate with Step directive. The function must return bool
+// and optionally an error. Other arguments declare steps which are executed if the predicate
+// returns true. Steps declared with Else directive are executed if the predicate returns false.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow(){
+//          effe.BuildFlow(
+//              effe.Step(step1),
+//              effe.If(effe.Step(isVip),
+//                  effe.Step(assignPersonalManager),
+//                  effe.Else(
+//                      effe.Step(assignQueue),
+//                  ),
+//              ),
+//          )
+//      }
+func If(predicate StepFunc, steps ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// This directive declares steps which are executed if the predicate returns false.
+// This directive can be used only in If.
+func Else(steps ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
 // Decision directive helps to organize branching of our business logic.
 // First argument can be a type or a field from the type.
 // Golang doesn't provide an opportunity to pass types to function argument.
@@ -118,6 +228,8 @@ func Before(fn interface{}) StepFunc { //nolint:unparam
 // If you want branching your logic by field from type you can declare it.
 // For that you need to create an empty object with your type and get field from it.
 // Other arguments declare handlers for every case. Every case can be declared with Case directive.
+// Default directive declares a handler if no case matches. Without Default directive
+// Effe generates a handler which returns an error.
 // Failure directive works here too. With Failure you can declare an error handler for case.
 //
 // Examples:
@@ -141,6 +253,16 @@ func Before(fn interface{}) StepFunc { //nolint:unparam
 //              ),
 //          )
 //      }
+//
+//      func BuildMyBusinessFlow3(){
+//          effe.BuildFlow(
+//              effe.Step(getOrderStatus),
+//              effe.Decision(new(OrderStatus),
+//                  effe.Case(OrderStatusNew, OrderStatusPending, effe.Step(step1)),
+//                  effe.Default(effe.Step(step2)),
+//              ),
+//          )
+//      }
 func Decision(tag interface{}, cases ...StepFunc) StepFunc { //nolint:unparam
 	panicDSLMethodNotFound()
 	return nil
@@ -148,8 +270,251 @@ func Decision(tag interface{}, cases ...StepFunc) StepFunc { //nolint:unparam
 
 // This directive can be used only in Decision.
 // Case declares the steps which will execute.
+// Case can take several keys: leading arguments which are not directives are keys.
 func Case(key CaseKey, funcs ...StepFunc) StepFunc { //nolint:unparam
 	panicDSLMethodNotFound()
 	return nil
 }
+
+// This directive can be used only in Decision.
+// Default declares the steps which will execute if no case matches.
+func Default(funcs ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// Parallel directive helps to declare a block of steps which are executed concurrently.
+// Every step is executed in its own goroutine and the block waits for all of them.
+// Steps in a block must be independent: a step can't use an output of another step
+// from the same block and two steps can't return values with the same type.
+// If one of steps returns an error, the block returns the first error. If a
+// context.Context is passed to the block, then it's cancelled after the first error.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow(){
+//          effe.BuildFlow(
+//              effe.Step(step1),
+//              effe.Parallel(
+//                  effe.Step(fetchUser),
+//                  effe.Step(fetchPricing),
+//                  effe.Step(fetchDriver),
+//              ),
+//              effe.Step(step2),
+//          )
+//      }
+func Parallel(steps ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// Retry directive helps to call a step again if it returns an error.
+// First argument declares the step. Other arguments configure retries:
+// Attempts is required and declares the maximum number of calls,
+// Backoff declares a delay between calls and RetryIf declares a function
+// which decides whether an error is retryable.
+// If a context.Context is passed to the step, then Effe stops retries after
+// the context is done.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow(){
+//          effe.BuildFlow(
+//              effe.Step(step1),
+//              effe.Retry(effe.Step(callPSP), effe.Attempts(3), effe.Backoff(100*time.Millisecond),
+//                  effe.RetryIf(isTemporaryErr),
+//              ),
+//          )
+//      }
+func Retry(step StepFunc, options ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// This directive declares the maximum number of calls of a step.
+// This directive can be used only in Retry.
+func Attempts(n int) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// This directive declares a delay before the second call of a step.
+// The delay is doubled before every next call.
+// This directive can be used only in Retry.
+func Backoff(d time.Duration) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// RetryIf works the same way as Step, but the function must take an error
+// and return bool. If the function returns false, then Effe stops retries
+// and returns the error.
+// This directive can be used only in Retry.
+func RetryIf(fn interface{}) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// Timeout directive helps to declare a block of steps which must finish in time.
+// First argument declares the duration. Other arguments declare steps.
+// Effe creates a new context.Context with the deadline for steps in the block, and
+// if a context is not passed to the flow, then it becomes an argument of the flow.
+// If one of steps returns an error after the deadline, then the block returns
+// an error with type *TimeoutError. It can be inspected by a Failure handler.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow(){
+//          effe.BuildFlow(
+//              effe.Step(step1),
+//              effe.Timeout(800*time.Millisecond,
+//                  effe.Step(step2),
+//                  effe.Step(step3),
+//              ),
+//          )
+//      }
+func Timeout(d time.Duration, steps ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// ForEach directive helps to call a block of steps for every element of a slice.
+// First argument declares the slice type with function new. Other arguments declare
+// steps and options. Steps take an element of the slice as an argument.
+// Collect declares a type which is returned by steps for every element, and Effe
+// collects these values into a slice with the same order.
+// Concurrency declares the maximum number of elements processed at the same time.
+// By default elements are processed one by one.
+//
+// Examples:
+//
+//      func BuildMyBusinessFlow(){
+//          effe.BuildFlow(
+//              effe.Step(loadOrders),
+//              effe.ForEach(new([]Order),
+//                  effe.Step(chargeOrder),
+//                  effe.Collect(new(Receipt)),
+//                  effe.Concurrency(4),
+//              ),
+//          )
+//      }
+func ForEach(slice interface{}, steps ...StepFunc) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// This directive declares a type which is collected into a slice for every element.
+// The type must be created with function new.
+// This directive can be used only in ForEach.
+func Collect(item interface{}) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// This directive declares the maximum number of elements processed at the same time.
+// After the first error new elements aren't processed.
+// This directive can be used only in ForEach.
+func Concurrency(n int) StepFunc { //nolint:unparam
+	panicDSLMethodNotFound()
+	return nil
+}
+
+// TimeoutError is returned by a block declared with Timeout directive
+// if the deadline of the block is exceeded.
+type TimeoutError struct {
+	// Name of the block with a name of the flow, names of steps and the duration,
+	// for example "Checkout.ReserveHotel-ChargeCard (5s)"
+	Name string
+	// Error which is returned by a step
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("deadline exceeded in %s: %s", e.Name, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Compensations collects compensations of succeeded steps in a flow.
+// It is used by generated code and it is safe for concurrent use.
+type Compensations struct {
+	mu  sync.Mutex
+	fns []func() error
+}
+
+// Add records a compensation of a succeeded step.
+func (c *Compensations) Add(fn func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fns = append(c.fns, fn)
+}
+
+// Run calls recorded compensations in reverse order and returns err.
+// If one of compensations returns an error, then Run returns an error
+// with type *CompensationError.
+func (c *Compensations) Run(err error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var compensationErrs []error
+	for i := len(c.fns) - 1; i >= 0; i-- {
+		if compensationErr := c.fns[i](); compensationErr != nil {
+			compensationErrs = append(compensationErrs, compensationErr)
+		}
+	}
+	c.fns = nil
+
+	if len(compensationErrs) > 0 {
+		return &CompensationError{
+			Err:              err,
+			CompensationErrs: compensationErrs,
+		}
+	}
+	return err
+}
+
+// CompensationError is returned by a flow if compensations of succeeded steps
+// return errors.
+type CompensationError struct {
+	// Error which is returned by the flow
+	Err error
+	// Errors which are returned by compensations
+	CompensationErrs []error
+}
+
+func (e *CompensationError) Error() string {
+	msgs := make([]string, len(e.CompensationErrs))
+	for i, compensationErr := range e.CompensationErrs {
+		msgs[i] = compensationErr.Error()
+	}
+	return fmt.Sprintf("%s: compensations failed: %s", e.Err, strings.Join(msgs, "; "))
+}
+
+func (e *CompensationError) Unwrap() error {
+	return e.Err
+}
+
+// StepError is returned by a flow generated with the wrap error plugin
+// in the step error mode. Use errors.As for finding which step failed.
+type StepError struct {
+	// Name of the flow
+	Flow string
+	// Name of the step
+	Step string
+	// Error which is returned by the step
+	Err error
+}
+
+func (e *StepError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("failure call %s in %s", e.Step, e.Flow)
+	}
+	return fmt.Sprintf("failure call %s in %s: %s", e.Step, e.Flow, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
 `
\ No newline at end of file
@@ -0,0 +1,199 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GettEngineering/effe/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+const effeErrsFileName = "effe_errs.txt"
+
+// GenerateFunc generates files for packages, for example it can be GenerateJSON
+// of a generator or GenerateDiagram with an output directory.
+type GenerateFunc func(ctx context.Context, wd string, env []string, patterns []string) ([]types.GenerateResult, []error)
+
+// loadOutputTestCase loads a test case, which expects generated files with the same names
+// as files in a directory want, or errors from a file effe_errs.txt.
+func loadOutputTestCase(root string, extGoFiles map[string][]byte) (*testCase, error) {
+	name := filepath.Base(root)
+	pkg, err := ioutil.ReadFile(filepath.Join(root, "pkg"))
+	if err != nil {
+		return nil, fmt.Errorf("load test case %s: %v", name, err)
+	}
+	test := &testCase{
+		name:        name,
+		pkg:         string(bytes.TrimSpace(pkg)),
+		wantOutputs: make(map[string][]byte),
+	}
+
+	wantEnts, err := ioutil.ReadDir(filepath.Join(root, "want"))
+	if err != nil {
+		return nil, fmt.Errorf("load test case %s: %v, if this is a new testcase, run updater", name, err)
+	}
+	for _, ent := range wantEnts {
+		if ent.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(root, "want", ent.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("load test case %s: %v", name, err)
+		}
+		if ent.Name() == effeErrsFileName {
+			test.wantEffeError = true
+			test.wantEffeErrorStrings = strings.Split(string(data), "\n")
+			continue
+		}
+		test.wantOutputs[ent.Name()] = data
+	}
+
+	test.goFiles, err = loadGoFiles(root, extGoFiles)
+	if err != nil {
+		return nil, fmt.Errorf("load test case %s: %v", name, err)
+	}
+	return test, nil
+}
+
+func loadOutputTestCases(testRoot string, goFiles map[string][]byte) ([]*testCase, []error) {
+	testdataEnts, err := ioutil.ReadDir(testRoot) // ReadDir sorts by name.
+	if err != nil {
+		return nil, []error{err}
+	}
+	tests := make([]*testCase, 0, len(testdataEnts))
+	errs := make([]error, 0)
+	for _, ent := range testdataEnts {
+		name := ent.Name()
+		if !ent.IsDir() || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			continue
+		}
+
+		test, err := loadOutputTestCase(filepath.Join(testRoot, name), goFiles)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tests = append(tests, test)
+	}
+	return tests, errs
+}
+
+// generateOutputs materializes a test case in a temporary GOPATH and returns contents of generated
+// files by names of files and scrubbed errors.
+func (test *testCase) generateOutputs(generate GenerateFunc, deps []string) (map[string][]byte, []string, error) {
+	gopath, err := ioutil.TempDir("", "effe_test")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(gopath)
+	gopath, err = filepath.EvalSymlinks(gopath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = test.materialize(gopath, deps); err != nil {
+		return nil, nil, err
+	}
+	wd := filepath.Join(gopath, "src", "example.com")
+
+	results, errs := generate(context.Background(), wd, append(os.Environ(), "GOPATH="+gopath), []string{test.pkg})
+	errStrings := make([]string, 0)
+	for _, err := range errs {
+		errStrings = append(errStrings, scrubError(gopath, err.Error()))
+	}
+	outputs := make(map[string][]byte)
+	for _, res := range results {
+		for _, err := range res.Errs {
+			errStrings = append(errStrings, scrubError(gopath, err.Error()))
+		}
+		if res.OutputPath == "" {
+			continue
+		}
+		content, err := ioutil.ReadFile(res.OutputPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		outputs[filepath.Base(res.OutputPath)] = content
+	}
+	return outputs, errStrings, nil
+}
+
+// RunOutputTests works as RunTests, but it compares every generated file with a file
+// with the same name in a directory want of a test case.
+func RunOutputTests(t *testing.T, generate GenerateFunc, testRoot string, goFiles map[string][]byte, deps []string) {
+	tests, errs := loadOutputTestCases(testRoot, goFiles)
+	for _, err := range errs {
+		t.Error(err)
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			outputs, errStrings, err := test.generateOutputs(generate, deps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.wantEffeError {
+				if diff := cmp.Diff(errStrings, test.wantEffeErrorStrings); diff != "" {
+					t.Errorf("Errors didn't match expected errors from effe_errors.txt:\n%s", diff)
+				}
+				return
+			}
+
+			assert.Empty(t, errStrings)
+			got := make(map[string]string, len(outputs))
+			for name, content := range outputs {
+				got[name] = string(content)
+			}
+			want := make(map[string]string, len(test.wantOutputs))
+			for name, content := range test.wantOutputs {
+				want[name] = string(content)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Fatalf("effe output differs from golden files:\n%s", diff)
+			}
+		})
+	}
+}
+
+// UpdateExpectedOutputs works as UpdateExpectedResult, but it replaces files in a directory want
+// of a test case with generated files.
+func UpdateExpectedOutputs(testRoot string, generate GenerateFunc, goFiles map[string][]byte, deps []string) {
+	tests, errs := loadOutputTestCases(testRoot, goFiles)
+	for _, err := range errs {
+		log.Println(err)
+	}
+	for _, test := range tests {
+		outputs, errStrings, err := test.generateOutputs(generate, deps)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		wantDir := filepath.Join(testRoot, test.name, "want")
+		for name := range test.wantOutputs {
+			if err = os.Remove(filepath.Join(wantDir, name)); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if test.wantEffeError {
+			if err = os.Remove(filepath.Join(wantDir, effeErrsFileName)); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if len(errStrings) > 0 {
+			outputs = map[string][]byte{effeErrsFileName: []byte(strings.Join(errStrings, "\n"))}
+		}
+		for name, content := range outputs {
+			if err = ioutil.WriteFile(filepath.Join(wantDir, name), content, 0600); err != nil {
+				log.Fatalf("can't write expected output %s: %s", name, err)
+			}
+		}
+	}
+}
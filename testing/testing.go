@@ -21,6 +21,8 @@ type testCase struct {
 	wantEffeOutput       []byte
 	wantEffeError        bool
 	wantEffeErrorStrings []string
+	// wantOutputs are contents of files which are generated for a test case by names of files
+	wantOutputs map[string][]byte
 }
 
 // nolint:gocognit
@@ -43,12 +45,28 @@ func loadTestCase(root string, extGoFiles map[string][]byte) (*testCase, error)
 		}
 	}
 
+	goFiles, err := loadGoFiles(root, extGoFiles)
+	if err != nil {
+		return nil, fmt.Errorf("load test case %s: %v", name, err)
+	}
+	return &testCase{
+		name:                 name,
+		pkg:                  string(bytes.TrimSpace(pkg)),
+		wantEffeOutput:       wantEffeOutput,
+		goFiles:              goFiles,
+		wantEffeError:        wantEffeError,
+		wantEffeErrorStrings: wantEffeErrorStrings,
+	}, nil
+}
+
+// loadGoFiles returns go files of a test case with the effe package and extGoFiles.
+func loadGoFiles(root string, extGoFiles map[string][]byte) (map[string][]byte, error) {
 	goFiles := map[string][]byte{}
 	for k, v := range extGoFiles {
 		goFiles[k] = v
 	}
 	goFiles["github.com/GettEngineering/effe/effe.go"] = []byte(SourceDSL)
-	err = filepath.Walk(root, func(src string, info os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(src string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -70,17 +88,7 @@ func loadTestCase(root string, extGoFiles map[string][]byte) (*testCase, error)
 		goFiles["example.com/"+filepath.ToSlash(rel)] = data
 		return nil
 	})
-	if err != nil {
-		return nil, fmt.Errorf("load test case %s: %v", name, err)
-	}
-	return &testCase{
-		name:                 name,
-		pkg:                  string(bytes.TrimSpace(pkg)),
-		wantEffeOutput:       wantEffeOutput,
-		goFiles:              goFiles,
-		wantEffeError:        wantEffeError,
-		wantEffeErrorStrings: wantEffeErrorStrings,
-	}, nil
+	return goFiles, err
 }
 
 // materialize creates a new GOPATH at the given directory, which may or
@@ -0,0 +1,3 @@
+package testmetricsplugin
+
+//go:generate go run ./cmd/updater/main.go -- ./testdata
@@ -0,0 +1,46 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe/plugins"
+	"time"
+)
+
+func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+	return func(metricsVal plugins.Metrics) error {
+		timeVal := time.Now()
+		err := service.Step1()
+		if err != nil {
+			metricsVal.ObserveFailure("BuildComponent2", "Step1", time.Since(timeVal))
+			return err
+		}
+		metricsVal.ObserveSuccess("BuildComponent2", "Step1", time.Since(timeVal))
+		timeVal2 := time.Now()
+		err = service.Step2()
+		if err != nil {
+			metricsVal.ObserveFailure("BuildComponent2", "Step2", time.Since(timeVal2))
+			return err
+		}
+		metricsVal.ObserveSuccess("BuildComponent2", "Step2", time.Since(timeVal2))
+		return nil
+	}
+}
+func NewBuildComponent2Impl() *BuildComponent2Impl {
+	return &BuildComponent2Impl{step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+
+type BuildComponent2Service interface {
+	Step1() error
+	Step2() error
+}
+type BuildComponent2Impl struct {
+	step1FieldFunc func() error
+	step2FieldFunc func() error
+}
+type BuildComponent2Func func(metricsVal plugins.Metrics) error
+
+func (b *BuildComponent2Impl) Step1() error { return b.step1FieldFunc() }
+func (b *BuildComponent2Impl) Step2() error { return b.step2FieldFunc() }
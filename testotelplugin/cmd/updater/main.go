@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	"github.com/GettEngineering/effe/plugins"
+	"github.com/GettEngineering/effe/strategies"
+	"github.com/GettEngineering/effe/testing"
+)
+
+func main() {
+	settings := generator.DefaultSettigs()
+	strategy := strategies.NewChain(
+		strategies.WithServiceObjectName(settings.LocalInterfaceVarname()),
+		strategies.Use(plugins.NewOtelTracing()),
+	)
+	gen := generator.NewGenerator(
+		generator.WithSetttings(settings),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithStrategy(strategy),
+	)
+
+	testing.UpdateExpectedResult(os.Args[2], gen, map[string][]byte{}, []string{})
+
+	// The module runtime compiles the generated code with an OpenTelemetry SDK
+	generated, err := ioutil.ReadFile(filepath.Join(os.Args[2], "Positive", "want", "effe_gen.go"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(os.Args[2], "..", "runtime", "effe_gen.go"), generated, 0600); err != nil {
+		log.Fatal(err)
+	}
+}
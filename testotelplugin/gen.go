@@ -0,0 +1,3 @@
+package testotelplugin
+
+//go:generate go run ./cmd/updater/main.go -- ./testdata
@@ -0,0 +1,53 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+	return func(ctx context.Context) error {
+		ctx2, spanVal := otel.Tracer("github.com/GettEngineering/effe").Start(ctx, "Step1", trace.WithAttributes(attribute.String("effe.flow", "BuildComponent2")))
+		err := service.Step1(ctx2)
+		if err != nil {
+			spanVal.RecordError(err)
+			spanVal.SetStatus(codes.Error, err.Error())
+			spanVal.End()
+			return err
+		}
+		spanVal.End()
+		_, spanVal2 := otel.Tracer("github.com/GettEngineering/effe").Start(ctx, "Step2", trace.WithAttributes(attribute.String("effe.flow", "BuildComponent2")))
+		err = service.Step2()
+		if err != nil {
+			spanVal2.RecordError(err)
+			spanVal2.SetStatus(codes.Error, err.Error())
+			spanVal2.End()
+			return err
+		}
+		spanVal2.End()
+		return nil
+	}
+}
+func NewBuildComponent2Impl() *BuildComponent2Impl {
+	return &BuildComponent2Impl{step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+
+type BuildComponent2Service interface {
+	Step1(ctx context.Context) error
+	Step2() error
+}
+type BuildComponent2Impl struct {
+	step1FieldFunc func(ctx context.Context) error
+	step2FieldFunc func() error
+}
+type BuildComponent2Func func(ctx context.Context) error
+
+func (b *BuildComponent2Impl) Step1(ctx context.Context) error { return b.step1FieldFunc(ctx) }
+func (b *BuildComponent2Impl) Step2() error                    { return b.step2FieldFunc() }
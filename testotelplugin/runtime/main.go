@@ -0,0 +1,5 @@
+// Package main contains code generated by the tracing plugin for the test case testdata/Positive.
+// It's a separate module, so the effe module doesn't depend on an OpenTelemetry SDK.
+package main
+
+func main() {}
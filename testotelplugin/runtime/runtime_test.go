@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingService struct {
+	err error
+}
+
+func (f failingService) Step1(ctx context.Context) error {
+	return nil
+}
+
+func (f failingService) Step2() error {
+	return f.err
+}
+
+func setupExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+	return exporter
+}
+
+func checkSpan(t *testing.T, span tracetest.SpanStub, name string, status codes.Code) {
+	t.Helper()
+	if span.Name != name {
+		t.Errorf("got span %q, want %q", span.Name, name)
+	}
+	if span.Status.Code != status {
+		t.Errorf("span %s: got status %v, want %v", name, span.Status.Code, status)
+	}
+	if span.InstrumentationLibrary.Name != "github.com/GettEngineering/effe" {
+		t.Errorf("span %s: got tracer %q", name, span.InstrumentationLibrary.Name)
+	}
+	want := attribute.String("effe.flow", "BuildComponent2")
+	for _, attr := range span.Attributes {
+		if attr == want {
+			return
+		}
+	}
+	t.Errorf("span %s: attribute %v not found in %v", name, want, span.Attributes)
+}
+
+func TestSpansOfSteps(t *testing.T) {
+	exporter := setupExporter(t)
+
+	flow := BuildComponent2(NewBuildComponent2Impl())
+	if err := flow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	checkSpan(t, spans[0], "Step1", codes.Unset)
+	checkSpan(t, spans[1], "Step2", codes.Unset)
+}
+
+func TestSpanOfFailedStep(t *testing.T) {
+	exporter := setupExporter(t)
+	stepErr := errors.New("step2 failed")
+
+	flow := BuildComponent2(failingService{err: stepErr})
+	if err := flow(context.Background()); err != stepErr {
+		t.Fatalf("got error %v, want %v", err, stepErr)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	checkSpan(t, spans[0], "Step1", codes.Unset)
+	checkSpan(t, spans[1], "Step2", codes.Error)
+	if spans[1].Status.Description != stepErr.Error() {
+		t.Errorf("got status description %q, want %q", spans[1].Status.Description, stepErr.Error())
+	}
+	if len(spans[1].Events) != 1 || spans[1].Events[0].Name != "exception" {
+		t.Errorf("error isn't recorded in span Step2: %v", spans[1].Events)
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import "context"
+
+func step1() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return nil
+	}
+}
+
+func step2() func() error {
+	return func() error {
+		return nil
+	}
+}
@@ -0,0 +1,42 @@
+package testotelplugin
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	"github.com/GettEngineering/effe/plugins"
+	"github.com/GettEngineering/effe/strategies"
+	eTesting "github.com/GettEngineering/effe/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOtelTracingPlugin(t *testing.T) {
+	settings := generator.DefaultSettigs()
+	strategy := strategies.NewChain(
+		strategies.WithServiceObjectName(settings.LocalInterfaceVarname()),
+		strategies.Use(plugins.NewOtelTracing()),
+	)
+	gen := generator.NewGenerator(
+		generator.WithSetttings(settings),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithStrategy(strategy),
+	)
+
+	dslGo, err := ioutil.ReadFile(filepath.Join("gen.go"))
+	assert.NoError(t, err)
+
+	eTesting.RunTests(t, gen, "testdata", map[string][]byte{"github.com/GettEngineering/effe/testotelplugin/gen.go": dslGo}, []string{"github.com/GettEngineering/effe/testotelplugin"})
+}
+
+// TestRuntimeCode checks that the module runtime, which tests spans of generated code,
+// contains the expected result of the test case Positive.
+func TestRuntimeCode(t *testing.T) {
+	want, err := ioutil.ReadFile(filepath.Join("testdata", "Positive", "want", "effe_gen.go"))
+	assert.NoError(t, err)
+	got, err := ioutil.ReadFile(filepath.Join("runtime", "effe_gen.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "run go generate to update the module runtime")
+}
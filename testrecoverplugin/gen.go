@@ -0,0 +1,3 @@
+package testrecoverplugin
+
+//go:generate go run ./cmd/updater/main.go -- ./testdata
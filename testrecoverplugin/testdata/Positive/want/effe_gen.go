@@ -0,0 +1,64 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"example.com/foo"
+	"fmt"
+	"runtime/debug"
+)
+
+func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+	return func(stringVal string) error {
+		UserVal, err := func() (_ User, panicErr error) {
+			defer func() {
+				if r := recover(); r != nil {
+					panicErr = fmt.Errorf("panic in step Step1: %v\n%s", r, debug.Stack())
+				}
+			}()
+			return service.Step1(stringVal)
+		}()
+		if err != nil {
+			err = service.Failure(err)
+			return err
+		}
+		err = func() (panicErr error) {
+			defer func() {
+				if r := recover(); r != nil {
+					panicErr = fmt.Errorf("panic in step Step2: %v\n%s", r, debug.Stack())
+				}
+			}()
+			return service.Step2(UserVal)
+		}()
+		if err != nil {
+			err = service.Failure(err)
+			return err
+		}
+		service.Step3(UserVal)
+		return nil
+	}
+}
+func NewBuildComponent2Impl() *BuildComponent2Impl {
+	return &BuildComponent2Impl{failureFieldFunc: failure(), step1FieldFunc: step1(), step2FieldFunc: step2(), step3FieldFunc: step3()}
+}
+
+type BuildComponent2Service interface {
+	Failure(err error) error
+	Step1(login string) (User, error)
+	Step2(user User) error
+	Step3(user User)
+}
+type BuildComponent2Impl struct {
+	failureFieldFunc func(err error) error
+	step1FieldFunc   func(login string) (User, error)
+	step2FieldFunc   func(user User) error
+	step3FieldFunc   func(user User)
+}
+type BuildComponent2Func func(stringVal string) error
+
+func (b *BuildComponent2Impl) Failure(err error) error          { return b.failureFieldFunc(err) }
+func (b *BuildComponent2Impl) Step1(login string) (User, error) { return b.step1FieldFunc(login) }
+func (b *BuildComponent2Impl) Step2(user User) error            { return b.step2FieldFunc(user) }
+func (b *BuildComponent2Impl) Step3(user User)                  { b.step3FieldFunc(user) }
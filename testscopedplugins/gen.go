@@ -0,0 +1,3 @@
+package testscopedplugins
+
+//go:generate go run ./cmd/updater/main.go -- ./testdata
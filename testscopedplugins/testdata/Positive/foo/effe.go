@@ -0,0 +1,24 @@
+// +build effeinject
+
+package main
+
+import (
+	"github.com/GettEngineering/effe"
+)
+
+//effe:payments
+func BuildComponent2() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.Step(step2),
+	)
+	return nil
+}
+
+func BuildComponent3() error {
+	effe.BuildFlow(
+		effe.Step(step1),
+		effe.Step(step2),
+	)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"log"
+	"log/slog"
+)
+
+func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+	return func(loggerPtrVal *slog.Logger) error {
+		loggerPtrVal.Info("step started", "flow", "BuildComponent2", "step", "Step1")
+		log.Printf("call step Step1\n")
+		err := service.Step1()
+		if err != nil {
+			loggerPtrVal.Error("step failed", "flow", "BuildComponent2", "step", "Step1", "error", err)
+			return err
+		}
+		loggerPtrVal.Info("step succeeded", "flow", "BuildComponent2", "step", "Step1")
+		loggerPtrVal.Info("step started", "flow", "BuildComponent2", "step", "Step2")
+		err = service.Step2()
+		if err != nil {
+			loggerPtrVal.Error("step failed", "flow", "BuildComponent2", "step", "Step2", "error", err)
+			return err
+		}
+		loggerPtrVal.Info("step succeeded", "flow", "BuildComponent2", "step", "Step2")
+		return nil
+	}
+}
+func BuildComponent3(service BuildComponent3Service) BuildComponent3Func {
+	return func() error {
+		log.Printf("call step Step1\n")
+		err := service.Step1()
+		if err != nil {
+			return err
+		}
+		err = service.Step2()
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+func NewBuildComponent2Impl() *BuildComponent2Impl {
+	return &BuildComponent2Impl{step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+func NewBuildComponent3Impl() *BuildComponent3Impl {
+	return &BuildComponent3Impl{step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+
+type BuildComponent2Service interface {
+	Step1() error
+	Step2() error
+}
+type BuildComponent2Impl struct {
+	step1FieldFunc func() error
+	step2FieldFunc func() error
+}
+type BuildComponent2Func func(loggerPtrVal *slog.Logger) error
+type BuildComponent3Service interface {
+	Step1() error
+	Step2() error
+}
+type BuildComponent3Impl struct {
+	step1FieldFunc func() error
+	step2FieldFunc func() error
+}
+type BuildComponent3Func func() error
+
+func (b *BuildComponent2Impl) Step1() error { return b.step1FieldFunc() }
+func (b *BuildComponent2Impl) Step2() error { return b.step2FieldFunc() }
+func (b *BuildComponent3Impl) Step1() error { return b.step1FieldFunc() }
+func (b *BuildComponent3Impl) Step2() error { return b.step2FieldFunc() }
@@ -0,0 +1,33 @@
+package testscopedplugins
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/GettEngineering/effe/generator"
+	"github.com/GettEngineering/effe/loaders"
+	"github.com/GettEngineering/effe/plugins"
+	"github.com/GettEngineering/effe/strategies"
+	eTesting "github.com/GettEngineering/effe/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopedPlugins(t *testing.T) {
+	settings := generator.DefaultSettigs()
+	strategy := strategies.NewChain(
+		strategies.WithServiceObjectName(settings.LocalInterfaceVarname()),
+		strategies.Use(plugins.NewSlogPlugin(), strategies.ForDirective("payments")),
+		strategies.Use(plugins.NewLogPlugin(), strategies.Not(strategies.ForSteps("Step2"))),
+	)
+	gen := generator.NewGenerator(
+		generator.WithSetttings(settings),
+		generator.WithLoader(loaders.NewLoader(loaders.WithPackages([]string{"effe"}))),
+		generator.WithStrategy(strategy),
+	)
+
+	dslGo, err := ioutil.ReadFile(filepath.Join("gen.go"))
+	assert.NoError(t, err)
+
+	eTesting.RunTests(t, gen, "testdata", map[string][]byte{"github.com/GettEngineering/effe/testscopedplugins/gen.go": dslGo}, []string{"github.com/GettEngineering/effe/testscopedplugins"})
+}
@@ -0,0 +1,3 @@
+package testslogplugin
+
+//go:generate go run ./cmd/updater/main.go -- ./testdata
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+)
+
+type Password string
+
+type Secret = Password
+
+type Payload = []byte
+
+func step1() func(ctx context.Context, login string, secret Secret) (Payload, error) {
+	return func(ctx context.Context, login string, secret Secret) (Payload, error) {
+		return Payload(secret), nil
+	}
+}
+
+func step2() func(payload Payload) (Secret, error) {
+	return func(payload Payload) (Secret, error) {
+		return Secret(payload), nil
+	}
+}
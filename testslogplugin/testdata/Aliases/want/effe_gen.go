@@ -0,0 +1,51 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"example.com/foo"
+	"log/slog"
+)
+
+func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+	return func(ctx context.Context, stringVal string, SecretVal Secret, loggerPtrVal *slog.Logger) (Secret, error) {
+		loggerPtrVal.Info("step started", "flow", "BuildComponent2", "step", "Step1", "login", stringVal, "secret", "[REDACTED]")
+		PayloadVal, err := service.Step1(ctx, stringVal, SecretVal)
+		if err != nil {
+			loggerPtrVal.Error("step failed", "flow", "BuildComponent2", "step", "Step1", "error", err)
+			return SecretVal, err
+		}
+		loggerPtrVal.Info("step succeeded", "flow", "BuildComponent2", "step", "Step1")
+		loggerPtrVal.Info("step started", "flow", "BuildComponent2", "step", "Step2")
+		SecretVal, err = service.Step2(PayloadVal)
+		if err != nil {
+			loggerPtrVal.Error("step failed", "flow", "BuildComponent2", "step", "Step2", "error", err)
+			return SecretVal, err
+		}
+		loggerPtrVal.Info("step succeeded", "flow", "BuildComponent2", "step", "Step2", "Password", "[REDACTED]")
+		return SecretVal, nil
+	}
+}
+func NewBuildComponent2Impl() *BuildComponent2Impl {
+	return &BuildComponent2Impl{step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+
+type BuildComponent2Service interface {
+	Step1(ctx context.Context, login string, secret Secret) (Payload, error)
+	Step2(payload Payload) (Secret, error)
+}
+type BuildComponent2Impl struct {
+	step1FieldFunc func(ctx context.Context, login string, secret Secret) (Payload, error)
+	step2FieldFunc func(payload Payload) (Secret, error)
+}
+type BuildComponent2Func func(ctx context.Context, stringVal string, SecretVal Secret, loggerPtrVal *slog.Logger) (Secret, error)
+
+func (b *BuildComponent2Impl) Step1(ctx context.Context, login string, secret Secret) (Payload, error) {
+	return b.step1FieldFunc(ctx, login, secret)
+}
+func (b *BuildComponent2Impl) Step2(payload Payload) (Secret, error) {
+	return b.step2FieldFunc(payload)
+}
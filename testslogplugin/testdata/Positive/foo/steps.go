@@ -0,0 +1,21 @@
+package main
+
+import "context"
+
+type Password string
+
+type User struct {
+	Login string
+}
+
+func step1() func(ctx context.Context, login string, password Password) (User, error) {
+	return func(ctx context.Context, login string, password Password) (User, error) {
+		return User{Login: login}, nil
+	}
+}
+
+func step2() func(user User, payload []byte) error {
+	return func(user User, payload []byte) error {
+		return nil
+	}
+}
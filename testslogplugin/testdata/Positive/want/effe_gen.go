@@ -0,0 +1,51 @@
+// Code generated by Effe. DO NOT EDIT.
+
+//+build !effeinject
+
+package main
+
+import (
+	"context"
+	"example.com/foo"
+	"log/slog"
+)
+
+func BuildComponent2(service BuildComponent2Service) BuildComponent2Func {
+	return func(ctx context.Context, stringVal string, PasswordVal Password, byteValAr []byte, loggerPtrVal *slog.Logger) error {
+		loggerPtrVal.Info("step started", "flow", "BuildComponent2", "step", "Step1", "login", stringVal, "password", "[REDACTED]")
+		UserVal, err := service.Step1(ctx, stringVal, PasswordVal)
+		if err != nil {
+			loggerPtrVal.Error("step failed", "flow", "BuildComponent2", "step", "Step1", "error", err)
+			return err
+		}
+		loggerPtrVal.Info("step succeeded", "flow", "BuildComponent2", "step", "Step1", "User", UserVal)
+		loggerPtrVal.Info("step started", "flow", "BuildComponent2", "step", "Step2", "user", UserVal)
+		err = service.Step2(UserVal, byteValAr)
+		if err != nil {
+			loggerPtrVal.Error("step failed", "flow", "BuildComponent2", "step", "Step2", "error", err)
+			return err
+		}
+		loggerPtrVal.Info("step succeeded", "flow", "BuildComponent2", "step", "Step2")
+		return nil
+	}
+}
+func NewBuildComponent2Impl() *BuildComponent2Impl {
+	return &BuildComponent2Impl{step1FieldFunc: step1(), step2FieldFunc: step2()}
+}
+
+type BuildComponent2Service interface {
+	Step1(ctx context.Context, login string, password Password) (User, error)
+	Step2(user User, payload []byte) error
+}
+type BuildComponent2Impl struct {
+	step1FieldFunc func(ctx context.Context, login string, password Password) (User, error)
+	step2FieldFunc func(user User, payload []byte) error
+}
+type BuildComponent2Func func(ctx context.Context, stringVal string, PasswordVal Password, byteValAr []byte, loggerPtrVal *slog.Logger) error
+
+func (b *BuildComponent2Impl) Step1(ctx context.Context, login string, password Password) (User, error) {
+	return b.step1FieldFunc(ctx, login, password)
+}
+func (b *BuildComponent2Impl) Step2(user User, payload []byte) error {
+	return b.step2FieldFunc(user, payload)
+}
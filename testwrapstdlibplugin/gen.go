@@ -0,0 +1,3 @@
+package testwrapstdlibplugin
+
+//go:generate go run ./cmd/updater/main.go -- ./testdata
@@ -0,0 +1,3 @@
+package testwrapsteperrorplugin
+
+//go:generate go run ./cmd/updater/main.go -- ./testdata
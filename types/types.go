@@ -15,21 +15,52 @@ type SimpleComponent struct {
 	Output           *ast.FieldList
 	FuncName         *ast.Ident
 	OriginalFuncName *ast.Ident
-	Deps             *ast.FieldList
+	// Package is an identifier of an imported package where the step is declared.
+	// It's nil for steps declared in the current package.
+	Package *ast.Ident
+	// Receiver is a type of a receiver if the step is declared with a method expression.
+	// The receiver is the only dependency of the step.
+	Receiver   ast.Expr
+	Deps       *ast.FieldList
+	Compensate *SimpleComponent
+	// As is a name of values returned by the step, it's empty for unnamed values.
+	As string
+	// NamedInputs contains names of arguments which take named values.
+	NamedInputs []string
 }
 
 func (s SimpleComponent) Name() *ast.Ident {
 	return s.FuncName
 }
 
-// CaseComponent is a result of parsing an expression with type effe.Case
+// CaseComponent is a result of parsing an expression with type effe.Case or effe.Default.
+// A component declared with effe.Default doesn't have tags.
 type CaseComponent struct {
 	Children []Component
-	Tag      ast.Expr
+	// Tag is the first key of the case
+	Tag ast.Expr
+	// Keys are other keys of the case declared with several keys
+	Keys []ast.Expr
+}
+
+// Tags returns all keys of the case
+func (c CaseComponent) Tags() []ast.Expr {
+	if c.Tag == nil {
+		return nil
+	}
+	return append([]ast.Expr{c.Tag}, c.Keys...)
 }
 
 func (c CaseComponent) Name() *ast.Ident {
-	caseVal := types.ExprString(c.Tag)
+	tags := c.Tags()
+	if len(tags) == 0 {
+		return ast.NewIdent("default")
+	}
+	caseVals := make([]string, len(tags))
+	for i, tag := range tags {
+		caseVals[i] = types.ExprString(tag)
+	}
+	caseVal := strings.Join(caseVals, ", ")
 	caseVal = strings.Replace(caseVal, "'", "\\'", -1)
 	caseVal = strings.Replace(caseVal, "\"", "", -1)
 	return ast.NewIdent(caseVal)
@@ -40,6 +71,7 @@ type WrapComponent struct {
 	Before   *SimpleComponent
 	Success  *SimpleComponent
 	Failure  *SimpleComponent
+	Finally  *SimpleComponent
 	Children []Component
 }
 
@@ -67,17 +99,88 @@ type DecisionComponent struct {
 	TagName *ast.Ident
 	TagType ast.Expr
 	Failure *SimpleComponent
+	Default *CaseComponent
 }
 
 func (d DecisionComponent) Name() *ast.Ident {
 	return ast.NewIdent(fmt.Sprintf("decision %s", d.TagName.Name))
 }
 
+// ParallelComponent is a result of parsing an expression with type effe.Parallel
+type ParallelComponent struct {
+	Children []Component
+}
+
+func (p ParallelComponent) Name() *ast.Ident {
+	nameParts := []string{"parallel", "["}
+	for _, child := range p.Children {
+		nameParts = append(nameParts, child.Name().Name)
+	}
+	nameParts = append(nameParts, "]")
+	return ast.NewIdent(strings.Join(nameParts, " "))
+}
+
+// RetryComponent is a result of parsing an expression with type effe.Retry
+type RetryComponent struct {
+	Child    Component
+	Attempts ast.Expr
+	Backoff  ast.Expr
+	RetryIf  *SimpleComponent
+}
+
+func (r RetryComponent) Name() *ast.Ident {
+	return ast.NewIdent(fmt.Sprintf("retry %s", r.Child.Name().Name))
+}
+
+// TimeoutComponent is a result of parsing an expression with type effe.Timeout
+type TimeoutComponent struct {
+	Duration ast.Expr
+	Children []Component
+}
+
+func (t TimeoutComponent) Name() *ast.Ident {
+	return ast.NewIdent(fmt.Sprintf("timeout %s", types.ExprString(t.Duration)))
+}
+
+// ForEachComponent is a result of parsing an expression with type effe.ForEach
+type ForEachComponent struct {
+	SliceType   ast.Expr
+	ItemType    ast.Expr
+	Collect     ast.Expr
+	Concurrency ast.Expr
+	Children    []Component
+}
+
+func (f ForEachComponent) Name() *ast.Ident {
+	return ast.NewIdent(fmt.Sprintf("for each %s", types.ExprString(f.ItemType)))
+}
+
+// IfComponent is a result of parsing an expression with type effe.If
+type IfComponent struct {
+	Predicate *SimpleComponent
+	Then      []Component
+	Else      []Component
+}
+
+func (i IfComponent) Name() *ast.Ident {
+	return ast.NewIdent(fmt.Sprintf("if %s", i.Predicate.Name()))
+}
+
 // Every component is used by the business process must implement this interface.
 type Component interface {
 	Name() *ast.Ident
 }
 
+// Flow is a business flow declared with effe.BuildFlow in a function.
+type Flow struct {
+	// Name is an identifier of the function which declares the flow.
+	Name       *ast.Ident
+	Components []Component
+	Failure    Component
+	// Directives are comments //effe:<directive> of the function which declares the flow.
+	Directives []string
+}
+
 // GenerateResult stores the result for a package from a call to Generate.
 type GenerateResult struct {
 	// PkgPath is the package's PkgPath.